@@ -3,8 +3,8 @@ package maze
 import (
 	"context"
 	"fmt"
-	"log/slog"
 	"os"
+	"os/signal"
 	"sync"
 	"time"
 
@@ -13,67 +13,136 @@ import (
 	"github.com/LLIEPJIOK/mazegenerator/internal/painter"
 	"github.com/LLIEPJIOK/mazegenerator/internal/pathfinder"
 	"github.com/LLIEPJIOK/mazegenerator/internal/presentation"
+	"github.com/LLIEPJIOK/mazegenerator/internal/renderer"
 )
 
-const pathDrawingDelay = 50 * time.Millisecond
-
-type pathFinder interface {
-	ShortestPath(maze domain.Maze, pathChan chan<- []domain.Coord) ([]domain.Coord, bool)
-}
+const (
+	pathDrawingDelay = 50 * time.Millisecond
+	// svgFileName is the file polar mazes are saved to, the terminal can only
+	// show them unrolled ring by ring.
+	svgFileName = "maze.svg"
+)
 
 func inputToMazeData(in *presentation.Input) domain.MazeData {
-	return domain.NewMazeData(in.Height, in.Width, in.Start, in.End)
+	data := domain.NewMazeData(in.Height, in.Width, in.Start, in.End)
+	data.Topology = in.Topology
+	data.Levels = in.Levels
+	data.Mask = in.Mask
+
+	return data
 }
 
-func generationAlgorithm(algo string) generator.Algorithm {
-	switch algo {
-	case "prim":
-		return generator.NewPrim()
+func Start() error {
+	input, output := os.Stdin, os.Stdout
+	pres := presentation.New(input, output)
+
+	inputData, err := pres.ProcessInput()
+	if err != nil {
+		return fmt.Errorf("process input: %w", err)
+	}
 
-	case "backtrack":
-		return generator.NewBacktrack()
+	mazeData := inputToMazeData(inputData)
 
-	default:
-		slog.Error("unknown generation algorithm")
+	gen, pathFinder, err := newAlgorithms(inputData)
+	if err != nil {
+		return err
 	}
 
-	return nil
-}
+	paint := painter.New(output, mazeData)
+
+	// Ctrl+C stops generation and path finding instead of killing the
+	// program in the middle of painting
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	maze, err := generate(ctx, gen, paint, mazeData)
+	if err != nil {
+		return fmt.Errorf("generate maze: %w", err)
+	}
 
-func pathFinderAlgorithm(algo string) pathFinder {
-	switch algo {
-	case "dijkstra":
-		return pathfinder.NewDijkstra()
+	path, ok, err := findPath(ctx, pathFinder, paint, maze)
+	if err != nil {
+		return fmt.Errorf("find path: %w", err)
+	}
 
-	case "a-star":
-		return pathfinder.NewAStar()
+	// scoring searches the maze once more, so it's done only when a
+	// difficulty was asked for
+	var score generator.Difficulty
 
-	default:
-		slog.Error("unknown path finder algorithm")
+	measured := inputData.Difficulty != (generator.Difficulty{})
+	if measured {
+		score, err = generator.Measure(ctx, maze)
+		if err != nil {
+			return fmt.Errorf("measure difficulty: %w", err)
+		}
 	}
 
-	return nil
-}
+	stop()
 
-func Start() error {
-	input, output := os.Stdin, os.Stdout
-	pres := presentation.New(input, output)
+	if !ok {
+		fmt.Println("There is no way between start and end points")
+	}
 
-	inputData, err := pres.ProcessInput()
+	if len(maze.Repaired) != 0 {
+		fmt.Printf("Walls opened to connect start and end: %d\n", len(maze.Repaired))
+	}
+
+	if measured {
+		fmt.Printf(
+			"Difficulty: path cost %d, decisions %d, dead ends %.0f%%\n",
+			score.PathCost,
+			score.Decisions,
+			100*score.DeadEnds,
+		)
+	}
+
+	fmt.Printf("Seed: %d\n", gen.Seed())
+
+	if err := exportSVG(maze, path); err != nil {
+		return err
+	}
+
+	return showFloors(pres, paint, maze.Data.Floors())
+}
+
+// newAlgorithms returns the generator and the path finder chosen in the
+// input.
+func newAlgorithms(inputData *presentation.Input) (*generator.Generator, pathfinder.PathFinder, error) {
+	genAlgo, err := generator.NewRegistry().Create(inputData.GenAlgo, inputData.GenParams)
 	if err != nil {
-		return fmt.Errorf("process input: %w", err)
+		return nil, nil, fmt.Errorf("create generation algorithm: %w", err)
 	}
 
-	mazeData := inputToMazeData(inputData)
+	pathFinder, err := pathfinder.NewRegistry().Create(inputData.PathFindAlgo, inputData.PathFindParams)
+	if err != nil {
+		return nil, nil, fmt.Errorf("create path finder algorithm: %w", err)
+	}
 
-	gen := generator.New(generationAlgorithm(inputData.GenAlgo))
-	paint := painter.New(output, mazeData)
-	pathFinder := pathFinderAlgorithm(inputData.PathFindAlgo)
-	paintingChan := make(chan domain.CellPaintingData)
+	gen := generator.New(
+		genAlgo,
+		generator.WithSeed(inputData.Seed),
+		generator.WithTerrain(inputData.Terrain),
+		generator.WithBiomes(inputData.Biomes),
+		generator.WithBraid(inputData.Braid),
+		generator.WithDifficulty(inputData.Difficulty),
+		generator.WithWorkers(inputData.Workers),
+		generator.WithMerge(inputData.Merge),
+	)
+
+	return gen, pathFinder, nil
+}
 
-	ctx, cancel := context.WithCancel(context.Background())
+// generate paints the maze while it's generated.
+func generate(
+	ctx context.Context,
+	gen *generator.Generator,
+	paint *painter.Painter,
+	mazeData domain.MazeData,
+) (domain.Maze, error) {
+	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
+	paintingChan := make(chan domain.CellPaintingData)
 	wg := &sync.WaitGroup{}
 	wg.Add(1)
 
@@ -82,17 +151,29 @@ func Start() error {
 		paint.PaintGeneration(ctx, paintingChan)
 	}()
 
-	maze, err := gen.GenerateMaze(mazeData, paintingChan)
+	maze, err := gen.GenerateMaze(ctx, mazeData, paintingChan)
 	if err != nil {
 		cancel()
-		return fmt.Errorf("generate maze: %w", err)
+		wg.Wait()
+
+		return domain.Maze{}, err
 	}
 
 	close(paintingChan)
 	wg.Wait()
 
-	pathChan := make(chan []domain.Coord)
+	return maze, nil
+}
 
+// findPath paints the paths explored while the shortest one is searched.
+func findPath(
+	ctx context.Context,
+	pathFinder pathfinder.PathFinder,
+	paint *painter.Painter,
+	maze domain.Maze,
+) ([]domain.Coord, bool, error) {
+	pathChan := make(chan []domain.Coord)
+	wg := &sync.WaitGroup{}
 	wg.Add(1)
 
 	go func() {
@@ -100,13 +181,65 @@ func Start() error {
 		paint.PaintPath(pathChan, pathDrawingDelay)
 	}()
 
-	_, ok := pathFinder.ShortestPath(maze, pathChan)
+	path, ok, err := pathFinder.ShortestPath(ctx, maze, pathChan)
 
 	close(pathChan)
 	wg.Wait()
 
-	if !ok {
-		fmt.Println("There is no way between start and end points")
+	return path, ok, err
+}
+
+// exportSVG saves polar mazes with the path, other mazes are only shown in
+// the terminal.
+func exportSVG(maze domain.Maze, path []domain.Coord) error {
+	if maze.Data.Topology != domain.Polar {
+		return nil
+	}
+
+	if err := saveSVG(maze, path); err != nil {
+		return fmt.Errorf("save svg: %w", err)
+	}
+
+	fmt.Printf("The maze is saved to %s\n", svgFileName)
+
+	return nil
+}
+
+// showFloors switches the shown floor of multi-level mazes until the user
+// exits.
+func showFloors(pres *presentation.Presentation, paint *painter.Painter, floors int) error {
+	if floors <= 1 {
+		return nil
+	}
+
+	for {
+		floor, ok, err := pres.NextFloor(paint.Floor(), floors)
+		if err != nil {
+			return fmt.Errorf("choose floor: %w", err)
+		}
+
+		if !ok {
+			return nil
+		}
+
+		paint.ShowFloor(floor)
+	}
+}
+
+func saveSVG(maze domain.Maze, path []domain.Coord) (err error) {
+	file, err := os.Create(svgFileName)
+	if err != nil {
+		return fmt.Errorf("create file: %w", err)
+	}
+
+	defer func() {
+		if closeErr := file.Close(); closeErr != nil && err == nil {
+			err = fmt.Errorf("close file: %w", closeErr)
+		}
+	}()
+
+	if err := renderer.NewSVG(file).Render(maze, path); err != nil {
+		return fmt.Errorf("render maze: %w", err)
 	}
 
 	return nil
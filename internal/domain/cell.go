@@ -12,6 +12,8 @@ const (
 	River
 	Ambiguous
 	Path
+	// Stairs lead to the stairs right above or below them.
+	Stairs
 )
 
 func (c CellType) String() string {
@@ -37,6 +39,9 @@ func (c CellType) String() string {
 	case Path:
 		// ANSI code for red background
 		return "\x1b[41m  \x1b[0m"
+	case Stairs:
+		// ANSI code for magenta background and white symbols
+		return "\033[45m\033[97m≡≡\033[0m"
 	}
 
 	return ""
@@ -54,6 +59,9 @@ func (c CellType) Cost() int {
 	case Money:
 		return 1
 
+	case Stairs:
+		return 5
+
 	case Sand:
 		return 5
 
@@ -67,6 +75,8 @@ func (c CellType) Cost() int {
 	return 0
 }
 
+// CellPaintingData is a change of the cell in the row and col of Maze.Cells,
+// so the row of a multi-level maze includes rows of the floors below.
 type CellPaintingData struct {
 	Row      int
 	Col      int
@@ -0,0 +1,68 @@
+package domain
+
+// ConstraintKind is what a constraint requires of its cell.
+type ConstraintKind int
+
+const (
+	// FixedWall cells stay walls.
+	FixedWall ConstraintKind = iota
+	// FixedPassage cells are carved with terrain chosen by the generator.
+	FixedPassage
+	// FixedTerrain cells are carved with the terrain of the constraint.
+	FixedTerrain
+)
+
+// Constraint fixes a cell of the maze before it's generated.
+type Constraint struct {
+	Kind ConstraintKind
+	// Terrain is the type of FixedTerrain cells.
+	Terrain CellType
+}
+
+func NewWallConstraint() Constraint {
+	return Constraint{
+		Kind: FixedWall,
+	}
+}
+
+func NewPassageConstraint() Constraint {
+	return Constraint{
+		Kind: FixedPassage,
+	}
+}
+
+func NewTerrainConstraint(terrain CellType) Constraint {
+	return Constraint{
+		Kind:    FixedTerrain,
+		Terrain: terrain,
+	}
+}
+
+// Constraints fix cells by their coordinates, so key features of a maze can
+// be placed by hand and the generator carves the rest. A nil map fixes
+// nothing.
+type Constraints map[Coord]Constraint
+
+// Wall reports whether the cell must stay a wall.
+func (c Constraints) Wall(coord Coord) bool {
+	constraint, ok := c[coord]
+
+	return ok && constraint.Kind == FixedWall
+}
+
+// Open reports whether the cell must be carved.
+func (c Constraints) Open(coord Coord) bool {
+	constraint, ok := c[coord]
+
+	return ok && constraint.Kind != FixedWall
+}
+
+// Terrain returns the type the cell must have if it's fixed.
+func (c Constraints) Terrain(coord Coord) (CellType, bool) {
+	constraint, ok := c[coord]
+	if !ok || constraint.Kind != FixedTerrain {
+		return Wall, false
+	}
+
+	return constraint.Terrain, true
+}
@@ -3,6 +3,8 @@ package domain
 type Coord struct {
 	Row int
 	Col int
+	// Level is the floor of a multi-level maze, 0 is the ground floor.
+	Level int
 }
 
 func NewCoord(rowID, colID int) Coord {
@@ -11,3 +13,11 @@ func NewCoord(rowID, colID int) Coord {
 		Col: colID,
 	}
 }
+
+func NewLevelCoord(level, rowID, colID int) Coord {
+	return Coord{
+		Row:   rowID,
+		Col:   colID,
+		Level: level,
+	}
+}
@@ -0,0 +1,97 @@
+package domain
+
+// Floors returns the number of floors, a maze always has at least one.
+func (d MazeData) Floors() int {
+	return max(d.Levels, 1)
+}
+
+// Rows returns the number of rows of all floors.
+func (d MazeData) Rows() int {
+	return d.Floors() * d.Height
+}
+
+// CellRow returns the row of Maze.Cells the coord lies in.
+func (d MazeData) CellRow(c Coord) int {
+	return c.Level*d.Height + c.Row
+}
+
+// CoordAt returns the coord of the cell in the row and col of Maze.Cells.
+func (d MazeData) CoordAt(row, col int) Coord {
+	return NewLevelCoord(row/d.Height, row%d.Height, col)
+}
+
+// Contains reports whether the coord is a cell of the maze allowed by its mask.
+func (d MazeData) Contains(c Coord) bool {
+	return c.Level >= 0 && c.Level < d.Floors() && d.Topology.Contains(c, d.Height, d.Width) && d.Mask.Allows(c)
+}
+
+// Neighbours returns cells adjacent to the coord on its floor and the cells
+// right above and below it.
+func (d MazeData) Neighbours(c Coord) []Coord {
+	if !d.Contains(c) {
+		return nil
+	}
+
+	return d.AppendNeighbours(make([]Coord, 0, d.Topology.Directions()+2), c)
+}
+
+// AppendNeighbours appends the neighbours of the coord to dst and returns the
+// extended slice, so a reused buffer saves an allocation per cell.
+func (d MazeData) AppendNeighbours(dst []Coord, c Coord) []Coord {
+	if !d.Contains(c) {
+		return dst
+	}
+
+	from := len(dst)
+	dst = d.Topology.AppendNeighbours(dst, c, d.Height, d.Width)
+
+	for i := from; i < len(dst); i++ {
+		dst[i].Level = c.Level
+	}
+
+	if d.Mask != nil {
+		allowed := dst[:from]
+
+		for _, next := range dst[from:] {
+			if d.Mask.Allows(next) {
+				allowed = append(allowed, next)
+			}
+		}
+
+		dst = allowed
+	}
+
+	if d.Floors() == 1 {
+		return dst
+	}
+
+	for _, level := range [...]int{c.Level - 1, c.Level + 1} {
+		if next := NewLevelCoord(level, c.Row, c.Col); d.Contains(next) {
+			dst = append(dst, next)
+		}
+	}
+
+	return dst
+}
+
+// Distance returns the minimum number of steps between cells including
+// floor changes.
+func (d MazeData) Distance(first, second Coord) int {
+	return d.Topology.Distance(first, second, d.Height, d.Width) + abs(first.Level-second.Level)
+}
+
+// Cell returns the type of the cell at the coord.
+func (m Maze) Cell(c Coord) CellType {
+	return m.Cells[m.Data.CellRow(c)][c.Col]
+}
+
+// Passable reports whether the cell to can be entered from the adjacent cell
+// from. Floors are connected only by stairs: cells right above each other are
+// passable only if both of them are stairs.
+func (m Maze) Passable(from, to Coord) bool {
+	if !m.Cell(to).IsTraversable() {
+		return false
+	}
+
+	return from.Level == to.Level || m.Cell(from) == Stairs && m.Cell(to) == Stairs
+}
@@ -0,0 +1,80 @@
+package domain
+
+// Mask marks cells of a maze which may be carved, so the maze takes the shape
+// of the mask. Cells outside the mask are permanent borders. A nil mask
+// allows every cell.
+type Mask [][]bool
+
+// Height returns the number of rows of the mask.
+func (m Mask) Height() int {
+	return len(m)
+}
+
+// Width returns the length of the longest row of the mask.
+func (m Mask) Width() int {
+	width := 0
+
+	for _, row := range m {
+		width = max(width, len(row))
+	}
+
+	return width
+}
+
+// Allows reports whether the cell may be carved.
+func (m Mask) Allows(c Coord) bool {
+	if m == nil {
+		return true
+	}
+
+	return c.Row >= 0 && c.Row < len(m) && c.Col >= 0 && c.Col < len(m[c.Row]) && m[c.Row][c.Col]
+}
+
+// OnBoundary reports whether the cell of the maze lies on its boundary: it's
+// allowed by the mask and one of its sides faces a cell outside the grid or
+// the mask.
+func (d MazeData) OnBoundary(c Coord) bool {
+	if !d.Contains(c) {
+		return false
+	}
+
+	dir := DefaultDirection()
+
+	for i := range dir.Rows {
+		next := NewLevelCoord(c.Level, c.Row+dir.Rows[i], c.Col+dir.Cols[i])
+		if !d.Contains(next) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Connected reports whether the second cell can be reached from the first one
+// through cells of the maze, so a mask of separate islands can't hold start
+// and end on different islands.
+func (d MazeData) Connected(first, second Coord) bool {
+	if !d.Contains(first) || !d.Contains(second) {
+		return false
+	}
+
+	seen := map[Coord]struct{}{first: {}}
+	queue := []Coord{first}
+
+	var buf [8]Coord
+
+	for head := 0; head < len(queue); head++ {
+		if queue[head] == second {
+			return true
+		}
+
+		for _, next := range d.AppendNeighbours(buf[:0], queue[head]) {
+			if _, ok := seen[next]; !ok {
+				seen[next] = struct{}{}
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	return false
+}
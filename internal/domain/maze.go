@@ -1,10 +1,19 @@
 package domain
 
 type MazeData struct {
-	Height int
-	Width  int
-	Start  Coord
-	End    Coord
+	Height   int
+	Width    int
+	Start    Coord
+	End      Coord
+	Topology Topology
+	// Levels is the number of floors. Floors are stacked in Maze.Cells one
+	// after another: the floor of level l takes rows from l*Height to
+	// (l+1)*Height-1.
+	Levels int
+	// Mask is the shape of every floor, nil for a rectangular maze.
+	Mask Mask
+	// Constraints are cells fixed before generation, nil for none.
+	Constraints Constraints
 }
 
 func NewMazeData(height, width int, start, end Coord) MazeData {
@@ -13,12 +22,26 @@ func NewMazeData(height, width int, start, end Coord) MazeData {
 		Width:  width,
 		Start:  start,
 		End:    end,
+		Levels: 1,
 	}
 }
 
+// NewLevelMazeData returns data of a maze with the given number of floors
+// which starts on the ground floor and ends on the top one.
+func NewLevelMazeData(levels, height, width int, start, end Coord) MazeData {
+	start.Level, end.Level = 0, levels-1
+
+	data := NewMazeData(height, width, start, end)
+	data.Levels = levels
+
+	return data
+}
+
 type Maze struct {
 	Data  MazeData
 	Cells [][]CellType
+	// Repaired lists walls opened by the generator to connect start and end.
+	Repaired []Coord
 }
 
 func NewMaze(data MazeData, cells [][]CellType) Maze {
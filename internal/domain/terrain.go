@@ -0,0 +1,75 @@
+package domain
+
+// TerrainProfile holds relative weights of traversable cell types in a maze.
+type TerrainProfile map[CellType]int
+
+// TerrainTypes returns cell types which can be placed into passages in the
+// order used for random sampling.
+func TerrainTypes() []CellType {
+	return []CellType{Passage, Money, Sand, River}
+}
+
+func DefaultTerrain() TerrainProfile {
+	return TerrainProfile{
+		Passage: 12,
+		Money:   1,
+		Sand:    1,
+		River:   1,
+	}
+}
+
+// TerrainPreset returns the profile by its name.
+func TerrainPreset(name string) (TerrainProfile, bool) {
+	switch name {
+	case "default":
+		return DefaultTerrain(), true
+
+	case "desert":
+		return TerrainProfile{
+			Passage: 4,
+			Money:   1,
+			Sand:    10,
+		}, true
+
+	case "swamp":
+		return TerrainProfile{
+			Passage: 5,
+			Sand:    3,
+			River:   7,
+		}, true
+
+	case "treasure-hunt":
+		return TerrainProfile{
+			Passage: 6,
+			Money:   6,
+			Sand:    2,
+			River:   1,
+		}, true
+	}
+
+	return nil, false
+}
+
+func (t TerrainProfile) Total() int {
+	total := 0
+
+	for _, tpe := range TerrainTypes() {
+		total += max(t[tpe], 0)
+	}
+
+	return total
+}
+
+// Share returns the share of cells with the given type in percent.
+func (t TerrainProfile) Share(tpe CellType) int {
+	total := t.Total()
+	if total == 0 {
+		if tpe == Passage {
+			return 100
+		}
+
+		return 0
+	}
+
+	return (max(t[tpe], 0)*100 + total/2) / total
+}
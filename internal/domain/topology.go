@@ -0,0 +1,314 @@
+package domain
+
+import (
+	"math"
+	"slices"
+)
+
+// Topology describes how cells of the maze grid are connected.
+type Topology int
+
+const (
+	// Square cells have 4 neighbours: up, down, left and right.
+	Square Topology = iota
+	// Hex cells have 6 neighbours. The grid is stored in "odd-r" offset
+	// coordinates: odd rows are shifted half a cell to the right.
+	Hex
+	// Polar grids consist of concentric rings: Row is the ring starting from
+	// the single centre cell and Col is the cell of the ring counted
+	// clockwise. Outer rings have more cells, see RingCells. The grid is
+	// stored as a rectangle as wide as the outer ring, cells beyond the end
+	// of a ring don't exist and always stay walls.
+	Polar
+	// Torus cells have 4 neighbours like square ones, but the edges wrap:
+	// leaving the right edge enters on the left and leaving the bottom edge
+	// enters on the top.
+	Torus
+)
+
+// hexDirections are steps to the neighbours of a hex cell in axial
+// coordinates (q, r). Opposite directions are 3 apart.
+var hexDirections = [...]Coord{
+	{Row: 0, Col: 1},
+	{Row: -1, Col: 1},
+	{Row: -1, Col: 0},
+	{Row: 0, Col: -1},
+	{Row: 1, Col: -1},
+	{Row: 1, Col: 0},
+}
+
+func (t Topology) String() string {
+	switch t {
+	case Square:
+		return "square"
+	case Hex:
+		return "hex"
+	case Polar:
+		return "polar"
+	case Torus:
+		return "torus"
+	}
+
+	return ""
+}
+
+// toAxial converts the offset coord to axial hex coordinates. Row stays the
+// same and Col becomes q.
+func toAxial(c Coord) Coord {
+	return NewCoord(c.Row, c.Col-(c.Row-(c.Row&1))/2)
+}
+
+func fromAxial(c Coord) Coord {
+	return NewCoord(c.Row, c.Col+(c.Row-(c.Row&1))/2)
+}
+
+// RingCells returns the number of cells in the ring of a polar grid. A ring
+// splits its cells in two when they become about twice as wide as they are
+// high, so all cells have roughly the same size.
+func RingCells(ring int) int {
+	cells := 1
+
+	for i := 1; i <= ring; i++ {
+		cellWidth := 2 * math.Pi * float64(i) / float64(cells)
+		cells *= max(int(math.Round(cellWidth)), 1)
+	}
+
+	return cells
+}
+
+// NewPolarMazeData returns data of a polar maze with the given number of rings
+// which starts at the centre and ends at the rim.
+func NewPolarMazeData(rings int) MazeData {
+	data := NewMazeData(rings, RingCells(rings-1), NewCoord(0, 0), NewCoord(rings-1, 0))
+	data.Topology = Polar
+
+	return data
+}
+
+// HasLattice reports whether cells are arranged in a regular lattice, so
+// Step, Between and SameLattice can be used.
+func (t Topology) HasLattice() bool {
+	return t != Polar
+}
+
+// Wraps reports whether the edges of the grid are joined, so it has no borders.
+func (t Topology) Wraps() bool {
+	return t == Torus
+}
+
+// Wrap returns the cell of the height×width grid the coord beyond its edges
+// stands for. Coords of grids which don't wrap stay the same.
+func (t Topology) Wrap(c Coord, height, width int) Coord {
+	if !t.Wraps() {
+		return c
+	}
+
+	c.Row = (c.Row%height + height) % height
+	c.Col = (c.Col%width + width) % width
+
+	return c
+}
+
+// Directions returns the maximum number of neighbours of a cell.
+func (t Topology) Directions() int {
+	switch t {
+	case Hex:
+		return len(hexDirections)
+	case Polar:
+		return RingCells(1)
+	case Square, Torus:
+	}
+
+	return len(DefaultDirection().Rows)
+}
+
+// RowLength returns the number of cells in the row of the grid with the given width.
+func (t Topology) RowLength(row, width int) int {
+	if t == Polar {
+		return min(RingCells(row), width)
+	}
+
+	return width
+}
+
+// Contains reports whether the coord is a cell of the height×width grid.
+func (t Topology) Contains(c Coord, height, width int) bool {
+	return min(c.Row, c.Col) >= 0 && c.Row < height && c.Col < t.RowLength(c.Row, width)
+}
+
+// Step returns the cell which is steps cells away from the coord in the
+// direction with the given index. It's defined for lattice topologies only
+// and doesn't wrap the result.
+func (t Topology) Step(c Coord, direction, steps int) Coord {
+	if t == Hex {
+		axial, dir := toAxial(c), hexDirections[direction]
+
+		return fromAxial(NewCoord(axial.Row+steps*dir.Row, axial.Col+steps*dir.Col))
+	}
+
+	dir := DefaultDirection()
+
+	return NewCoord(c.Row+steps*dir.Rows[direction], c.Col+steps*dir.Cols[direction])
+}
+
+// Neighbours returns cells adjacent to the coord inside the height×width grid.
+func (t Topology) Neighbours(c Coord, height, width int) []Coord {
+	return t.AppendNeighbours(make([]Coord, 0, t.Directions()), c, height, width)
+}
+
+// AppendNeighbours appends cells adjacent to the coord inside the
+// height×width grid to dst and returns the extended slice. Hot loops pass a
+// reused buffer to avoid allocating a slice for every cell.
+func (t Topology) AppendNeighbours(dst []Coord, c Coord, height, width int) []Coord {
+	if !t.Contains(c, height, width) {
+		return dst
+	}
+
+	switch t {
+	case Polar:
+		return appendPolarNeighbours(dst, c, height, width)
+
+	case Square:
+		return appendSquareNeighbours(dst, c, height, width)
+	}
+
+	from := len(dst)
+
+	for i := range t.Directions() {
+		next := t.Wrap(t.Step(c, i, 1), height, width)
+
+		// narrow tori reach the same cell both ways
+		if t.Contains(next, height, width) && next != c && !slices.Contains(dst[from:], next) {
+			dst = append(dst, next)
+		}
+	}
+
+	return dst
+}
+
+// appendSquareNeighbours appends the cells above, below, to the left and to
+// the right of the coord, the same order as DefaultDirection, without
+// building directions for every cell.
+func appendSquareNeighbours(dst []Coord, c Coord, height, width int) []Coord {
+	if c.Row > 0 {
+		dst = append(dst, NewCoord(c.Row-1, c.Col))
+	}
+
+	if c.Row+1 < height {
+		dst = append(dst, NewCoord(c.Row+1, c.Col))
+	}
+
+	if c.Col > 0 {
+		dst = append(dst, NewCoord(c.Row, c.Col-1))
+	}
+
+	if c.Col+1 < width {
+		dst = append(dst, NewCoord(c.Row, c.Col+1))
+	}
+
+	return dst
+}
+
+// appendPolarNeighbours appends the cells next to the coord in its ring, the
+// cell of the inner ring it lies on and the cells of the outer ring lying on it.
+func appendPolarNeighbours(dst []Coord, c Coord, height, width int) []Coord {
+	cells := Polar.RowLength(c.Row, width)
+
+	if cells > 1 {
+		left, right := NewCoord(c.Row, (c.Col+cells-1)%cells), NewCoord(c.Row, (c.Col+1)%cells)
+
+		dst = append(dst, left)
+		if right != left {
+			dst = append(dst, right)
+		}
+	}
+
+	if c.Row > 0 {
+		dst = append(dst, NewCoord(c.Row-1, c.Col*Polar.RowLength(c.Row-1, width)/cells))
+	}
+
+	if c.Row+1 < height {
+		ratio := Polar.RowLength(c.Row+1, width) / cells
+
+		for i := range ratio {
+			dst = append(dst, NewCoord(c.Row+1, c.Col*ratio+i))
+		}
+	}
+
+	return dst
+}
+
+// Between returns the cell in the middle of two cells of the height×width
+// grid which are 2 steps apart in one direction. On a torus the direct way is
+// preferred to the one across the edge.
+func (t Topology) Between(first, second Coord, height, width int) Coord {
+	if t.Wraps() {
+		dr, dc := wrapStep(second.Row-first.Row, height), wrapStep(second.Col-first.Col, width)
+
+		return t.Wrap(NewCoord(first.Row+dr/2, first.Col+dc/2), height, width)
+	}
+
+	if t == Hex {
+		a, b := toAxial(first), toAxial(second)
+
+		return fromAxial(NewCoord((a.Row+b.Row)/2, (a.Col+b.Col)/2))
+	}
+
+	return NewCoord((first.Row+second.Row)/2, (first.Col+second.Col)/2)
+}
+
+// SameLattice reports whether cells are an even number of steps apart along
+// every axis. Such cells form a lattice of rooms which are 2 steps apart and
+// every other cell lies between exactly two of them.
+func (t Topology) SameLattice(first, second Coord) bool {
+	if t == Hex {
+		first, second = toAxial(first), toAxial(second)
+	}
+
+	return (first.Row-second.Row)%2 == 0 && (first.Col-second.Col)%2 == 0
+}
+
+// wrapStep turns the difference of coordinates which are at most 2 steps
+// apart on a wrapped axis of the given length into the steps from the first
+// to the second one.
+func wrapStep(diff, length int) int {
+	switch {
+	case diff > 2:
+		return diff - length
+	case diff < -2:
+		return diff + length
+	}
+
+	return diff
+}
+
+// Distance returns the minimum number of steps between cells of the
+// height×width grid. On polar grids it's the number of rings between cells,
+// which never exceeds the real one. On tori it's the Manhattan distance which
+// may go across the edges.
+func (t Topology) Distance(first, second Coord, height, width int) int {
+	switch t {
+	case Torus:
+		dr, dc := abs(first.Row-second.Row), abs(first.Col-second.Col)
+
+		return min(dr, height-dr) + min(dc, width-dc)
+	case Polar:
+		return abs(first.Row - second.Row)
+	case Hex:
+		a, b := toAxial(first), toAxial(second)
+		dq, dr := a.Col-b.Col, a.Row-b.Row
+
+		return (abs(dq) + abs(dr) + abs(dq+dr)) / 2
+	case Square:
+	}
+
+	return abs(first.Row-second.Row) + abs(first.Col-second.Col)
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+
+	return x
+}
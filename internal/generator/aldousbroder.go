@@ -0,0 +1,70 @@
+package generator
+
+import (
+	"context"
+
+	"github.com/LLIEPJIOK/mazegenerator/internal/domain"
+	"github.com/LLIEPJIOK/mazegenerator/internal/registry"
+)
+
+// AldousBroder walks randomly over the rooms and joins every room it enters
+// for the first time with the previous one. Like Wilson it samples all mazes
+// uniformly, but it is slower. The walk is made once from the start, because
+// united walks would bias the maze.
+type AldousBroder struct{}
+
+func NewAldousBroder() *AldousBroder {
+	return &AldousBroder{}
+}
+
+func (a *AldousBroder) singleOrigin() {}
+
+func (a *AldousBroder) createMazeCellsFromCoord(
+	ctx context.Context,
+	grd grid,
+	start domain.Coord,
+	rnd *randomSource,
+	drawingChan chan<- cell,
+) ([][]domain.CellType, error) {
+	if err := grd.requireLattice(); err != nil {
+		return nil, err
+	}
+
+	cells := grd.newCells()
+
+	carve := func(coord domain.Coord) {
+		tpe := rnd.cellType()
+		cells[coord.Row][coord.Col] = tpe
+		draw(drawingChan, newCell(coord.Row, coord.Col, tpe, drawingDelay))
+	}
+
+	carve(start)
+
+	var buf [maxNeighbours]domain.Coord
+
+	for cur, unvisited := start, len(grd.rooms(start))-1; unvisited > 0; {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		neighbours := grd.appendRoomNeighbours(buf[:0], cur)
+		next := neighbours[rnd.IntN(len(neighbours))]
+
+		if cells[next.Row][next.Col] == domain.Wall {
+			carve(grd.between(cur, next))
+			carve(next)
+
+			unvisited--
+		}
+
+		cur = next
+	}
+
+	return cells, nil
+}
+
+func aldousBroderEntry() registry.Entry[Algorithm] {
+	return plainEntry("aldous-broder", "Aldous-Broder algorithm, an unbiased but slow random walk", supportsLattice, func() Algorithm {
+		return NewAldousBroder()
+	})
+}
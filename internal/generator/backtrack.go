@@ -1,88 +1,69 @@
 package generator
 
 import (
-	"crypto/rand"
-	"fmt"
-	"math/big"
+	"context"
 
 	"github.com/LLIEPJIOK/mazegenerator/internal/domain"
+	"github.com/LLIEPJIOK/mazegenerator/internal/registry"
 )
 
-type Backtrack struct {
-	dir domain.Direction
-}
+type Backtrack struct{}
 
 func NewBacktrack() *Backtrack {
-	return &Backtrack{
-		dir: domain.DefaultDirection(),
-	}
+	return &Backtrack{}
 }
 
-const (
-	forkCoeff = 3
-)
-
 func (b *Backtrack) createMazeCellsFromCoord(
-	height, width int,
+	ctx context.Context,
+	grd grid,
 	start domain.Coord,
+	rnd *randomSource,
 	drawingChan chan<- cell,
 ) ([][]domain.CellType, error) {
-	cells := make([][]domain.CellType, height)
-
-	for i := range height {
-		cells[i] = make([]domain.CellType, width)
-	}
+	cells := grd.newCells()
 
 	stack := []domain.Coord{start}
 
+	var buf [maxNeighbours]domain.Coord
+
 	for len(stack) > 0 {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
 		curCoord := stack[len(stack)-1]
 		stack = stack[:len(stack)-1]
 
-		cntWalls := 0
-
-		for i := range b.dir.Rows {
-			newRow, newCol := curCoord.Row+b.dir.Rows[i], curCoord.Col+b.dir.Cols[i]
-			if min(newRow, newCol) < 0 || newRow >= height || newCol >= width ||
-				cells[newRow][newCol] == domain.Wall {
-				cntWalls++
-				continue
-			}
-		}
-
-		if cntWalls < 3 {
+		// cells fixed as passages are carved even if they make a loop
+		if grd.countPassages(cells, curCoord) > 1 && !grd.constraints.Open(curCoord) {
 			continue
 		}
 
-		tpe, err := randomCellType()
-		if err != nil {
-			return nil, fmt.Errorf("getting random cell type: %w", err)
-		}
-
-		cells[curCoord.Row][curCoord.Col] = tpe
-		drawingChan <- newCell(curCoord.Row, curCoord.Col, tpe, drawingDelay)
+		grd.carve(cells, curCoord, grd.terrain(curCoord, rnd), drawingChan, drawingDelay)
 
-		prevRands := make(map[int64]struct{})
+		neighbours := grd.appendNeighbours(buf[:0], curCoord)
+		rnd.Shuffle(len(neighbours), func(i, j int) {
+			neighbours[i], neighbours[j] = neighbours[j], neighbours[i]
+		})
 
-		for len(prevRands) != forkCoeff {
-			randID, err := rand.Int(rand.Reader, big.NewInt(int64(len(b.dir.Rows))))
-			if err != nil {
-				return nil, fmt.Errorf("generate random processID: %w", err)
-			}
-
-			if _, ok := prevRands[randID.Int64()]; ok {
-				continue
-			}
-
-			newRow, newCol := curCoord.Row+b.dir.Rows[randID.Int64()], curCoord.Col+b.dir.Cols[randID.Int64()]
+		// like forkCoeff directions of 4 used to, all neighbours but one
+		// random one are tried
+		if len(neighbours) > 1 {
+			neighbours = neighbours[:len(neighbours)-1]
+		}
 
-			if newRow >= 0 && newRow < height && newCol >= 0 && newCol < width && cells[newRow][newCol] == domain.Wall {
-				stack = append(stack, domain.NewCoord(newRow, newCol))
+		for _, next := range neighbours {
+			if grd.cell(cells, next) == domain.Wall && grd.grows(curCoord, next, rnd) {
+				stack = append(stack, next)
 			}
-
-			prevRands[randID.Int64()] = struct{}{}
 		}
 	}
 
 	return cells, nil
 }
+
+func backtrackEntry() registry.Entry[Algorithm] {
+	return plainEntry("backtrack", "recursive backtracking, long winding corridors", nil, func() Algorithm {
+		return NewBacktrack()
+	})
+}
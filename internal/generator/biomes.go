@@ -0,0 +1,169 @@
+package generator
+
+import (
+	"cmp"
+	"context"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/LLIEPJIOK/mazegenerator/internal/domain"
+)
+
+const (
+	biomeDelay = 300 * time.Microsecond
+	riverScale = 12
+	patchScale = 7
+)
+
+// biomeLayer picks cells for one terrain type: cells with the lowest score
+// get the type first.
+type biomeLayer struct {
+	tpe   domain.CellType
+	score func(coord domain.Coord) float64
+}
+
+// biomeLayers returns layers in the order they are applied. Rivers follow a
+// contour line of the noise, so they form continuous bands, while sand and
+// money take the noise peaks and form patches.
+func biomeLayers(rnd *randomSource) []biomeLayer {
+	river := newValueNoise(rnd.Uint64(), riverScale)
+	sand := newValueNoise(rnd.Uint64(), patchScale)
+	money := newValueNoise(rnd.Uint64(), patchScale)
+
+	return []biomeLayer{
+		{
+			tpe: domain.River,
+			score: func(coord domain.Coord) float64 {
+				return math.Abs(river.at(coord.Row, coord.Col) - 0.5)
+			},
+		},
+		{
+			tpe: domain.Sand,
+			score: func(coord domain.Coord) float64 {
+				return -sand.at(coord.Row, coord.Col)
+			},
+		},
+		{
+			tpe: domain.Money,
+			score: func(coord domain.Coord) float64 {
+				return -money.at(coord.Row, coord.Col)
+			},
+		},
+	}
+}
+
+// paintBiomes reassigns types of all passages with coherent noise keeping
+// the shares of the terrain profile. Cells with fixed terrain keep it.
+func paintBiomes(
+	ctx context.Context,
+	maze domain.Maze,
+	rnd *randomSource,
+	drawingChan chan<- domain.CellPaintingData,
+	processID int,
+) (domain.Maze, error) {
+	passages := biomePassages(maze)
+	types := biomeTypes(passages, rnd)
+	cells := newGrid(maze.Data).newCells()
+
+	for i, row := range maze.Cells {
+		copy(cells[i], row)
+	}
+
+	for i, coord := range passages {
+		tpe := types[i]
+
+		if row := maze.Data.CellRow(coord); cells[row][coord.Col] != tpe {
+			cells[row][coord.Col] = tpe
+			err := send(ctx, drawingChan, domain.NewCellPaintingData(row, coord.Col, tpe, processID, biomeDelay))
+			if err != nil {
+				return domain.Maze{}, err
+			}
+		}
+	}
+
+	return domain.NewMaze(maze.Data, cells), nil
+}
+
+// biomePassages returns the cells biomes can change. Stairs keep their type,
+// so floors stay connected.
+func biomePassages(maze domain.Maze) []domain.Coord {
+	passages := make([]domain.Coord, 0)
+
+	for i, row := range maze.Cells {
+		for j, tpe := range row {
+			if tpe == domain.Wall || tpe == domain.Stairs {
+				continue
+			}
+
+			if _, fixed := maze.Data.Constraints.Terrain(maze.Data.CoordAt(i, j)); !fixed {
+				passages = append(passages, maze.Data.CoordAt(i, j))
+			}
+		}
+	}
+
+	return passages
+}
+
+// biomeTypes returns the types of the passages, indexed like them.
+func biomeTypes(passages []domain.Coord, rnd *randomSource) []domain.CellType {
+	total := rnd.terrain.Total()
+	// walls mark passages which haven't got a type yet
+	types := make([]domain.CellType, len(passages))
+	scores := make([]float64, len(passages))
+
+	for _, layer := range biomeLayers(rnd) {
+		if total == 0 {
+			break
+		}
+
+		count := len(passages) * max(rnd.terrain[layer.tpe], 0) / total
+		candidates := make([]int, len(passages))
+
+		for i, coord := range passages {
+			scores[i] = layer.score(coord)
+			candidates[i] = i
+		}
+
+		slices.SortStableFunc(candidates, func(a, b int) int {
+			return cmp.Compare(scores[a], scores[b])
+		})
+
+		for _, id := range candidates {
+			if count == 0 {
+				break
+			}
+
+			if types[id] == domain.Wall {
+				types[id] = layer.tpe
+				count--
+			}
+		}
+	}
+
+	fallback := biomeFallback(rnd.terrain)
+
+	for i, tpe := range types {
+		if tpe == domain.Wall {
+			types[i] = fallback
+		}
+	}
+
+	return types
+}
+
+// biomeFallback returns the type of cells left after rounding: passages or
+// the most common type if the profile has no passages.
+func biomeFallback(terrain domain.TerrainProfile) domain.CellType {
+	fallback := domain.Passage
+
+	if terrain[domain.Passage] <= 0 {
+		for _, tpe := range domain.TerrainTypes() {
+			if terrain[tpe] > terrain[fallback] {
+				fallback = tpe
+			}
+		}
+	}
+
+	return fallback
+}
@@ -0,0 +1,170 @@
+package generator
+
+import (
+	"context"
+	"math"
+
+	"github.com/LLIEPJIOK/mazegenerator/internal/domain"
+)
+
+const DefaultBraid = 0.0
+
+func (g grid) isDeadEnd(cells [][]domain.CellType, coord, start, end domain.Coord) bool {
+	return coord != start && coord != end && g.cell(cells, coord) != domain.Wall &&
+		g.countPassages(cells, coord) == 1
+}
+
+// loopWalls returns walls next to the dead end which lead to other passages,
+// so opening any of them makes a loop. Walls fixed by constraints stay.
+func (g grid) loopWalls(cells [][]domain.CellType, deadEnd domain.Coord) []domain.Coord {
+	var buf [maxNeighbours]domain.Coord
+
+	res := make([]domain.Coord, 0, g.topology.Directions())
+
+	for _, neighbour := range g.appendNeighbours(buf[:0], deadEnd) {
+		if g.cell(cells, neighbour) == domain.Wall && !g.constraints.Wall(neighbour) &&
+			g.countPassages(cells, neighbour) > 1 {
+			res = append(res, neighbour)
+		}
+	}
+
+	return res
+}
+
+// deadEndCorridor returns cells of the corridor which leads to the dead end.
+// The corridor ends at a fork of the original maze or at the start or end,
+// so removing several dead ends one by one doesn't eat the maze up.
+func (g grid) deadEndCorridor(
+	cells [][]domain.CellType,
+	deadEnd, start, end domain.Coord,
+	forks [][]bool,
+) []domain.Coord {
+	var buf [maxNeighbours]domain.Coord
+
+	corridor := []domain.Coord{deadEnd}
+
+	for prev, cur := deadEnd, g.appendPassageNeighbours(buf[:0], cells, deadEnd)[0]; cur != start && cur != end; {
+		if forks[g.row(cur)][cur.Col] {
+			break
+		}
+
+		neighbours := g.appendPassageNeighbours(buf[:0], cells, cur)
+		if len(neighbours) != 2 {
+			// the other end of an isolated corridor
+			if len(neighbours) == 1 {
+				corridor = append(corridor, cur)
+			}
+
+			break
+		}
+
+		corridor = append(corridor, cur)
+
+		next := neighbours[0]
+		if next == prev {
+			next = neighbours[1]
+		}
+
+		prev, cur = cur, next
+	}
+
+	return corridor
+}
+
+// braid turns the braid factor share of dead ends of the merged maze into
+// loops by joining them with a neighbouring passage and leaves the others as
+// they are, so 0 keeps the maze as it's generated and 1 leaves no dead ends
+// but the start and the end. A chosen dead end which can't be joined with
+// other passages is filled instead. Filling stops at cells fixed as
+// passages. Changed cells are sent to paintingChan with SenderID 0.
+func (g *Generator) braid(
+	ctx context.Context,
+	maze domain.Maze,
+	rnd *randomSource,
+	paintingChan chan<- domain.CellPaintingData,
+) error {
+	if g.braidFactor == 0 {
+		return nil
+	}
+
+	var drawingChan chan cell
+
+	if paintingChan != nil {
+		drawingChan = make(chan cell)
+		forwarded := make(chan struct{})
+
+		go func() {
+			defer close(forwarded)
+
+			forward(ctx, paintingChan, drawingChan, 0)
+		}()
+
+		defer func() {
+			close(drawingChan)
+			<-forwarded
+		}()
+	}
+
+	return g.braidCells(ctx, newGrid(maze.Data), maze.Cells, maze.Data.Start, maze.Data.End, rnd, drawingChan)
+}
+
+func (g *Generator) braidCells(
+	ctx context.Context,
+	grd grid,
+	cells [][]domain.CellType,
+	start, end domain.Coord,
+	rnd *randomSource,
+	drawingChan chan<- cell,
+) error {
+	deadEnds := make([]domain.Coord, 0)
+	forks := newFlatCells[bool](len(cells), grd.width)
+
+	for i, row := range cells {
+		for j, tpe := range row {
+			coord := grd.data().CoordAt(i, j)
+
+			switch cntPassages := grd.countPassages(cells, coord); {
+			case tpe == domain.Wall || coord == start || coord == end:
+			case cntPassages == 1:
+				deadEnds = append(deadEnds, coord)
+			case cntPassages > 2:
+				forks[i][j] = true
+			}
+		}
+	}
+
+	rnd.Shuffle(len(deadEnds), func(i, j int) {
+		deadEnds[i], deadEnds[j] = deadEnds[j], deadEnds[i]
+	})
+
+	loops := int(math.Round(g.braidFactor * float64(len(deadEnds))))
+
+	for _, deadEnd := range deadEnds[:loops] {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		// one of the previous dead ends might have been joined with it
+		if !grd.isDeadEnd(cells, deadEnd, start, end) {
+			continue
+		}
+
+		walls := grd.loopWalls(cells, deadEnd)
+		if len(walls) == 0 {
+			for _, coord := range grd.deadEndCorridor(cells, deadEnd, start, end, forks) {
+				if grd.constraints.Open(coord) {
+					break
+				}
+
+				grd.fill(cells, coord, drawingChan, clearDelay)
+			}
+
+			continue
+		}
+
+		wall := walls[rnd.IntN(len(walls))]
+		grd.carve(cells, wall, grd.terrain(wall, rnd), drawingChan, clearDelay)
+	}
+
+	return nil
+}
@@ -0,0 +1,283 @@
+package generator
+
+import (
+	"context"
+
+	"github.com/LLIEPJIOK/mazegenerator/internal/domain"
+	"github.com/LLIEPJIOK/mazegenerator/internal/registry"
+)
+
+const (
+	DefaultCaveFill       = 0.45
+	DefaultCaveIterations = 4
+)
+
+// CaveRule holds thresholds of the cellular automaton which smooths caves,
+// counted among the 8 cells around a cell. An open cell becomes a wall if at
+// least Birth of them are walls and a wall stays if at least Survival of them are.
+type CaveRule struct {
+	Birth    int
+	Survival int
+}
+
+func NewCaveRule(birth, survival int) CaveRule {
+	return CaveRule{
+		Birth:    birth,
+		Survival: survival,
+	}
+}
+
+// DefaultCaveRule is the classic 4-5 rule which turns noise into smooth caves.
+func DefaultCaveRule() CaveRule {
+	return NewCaveRule(5, 4)
+}
+
+// Cave fills the grid with walls at random and smooths it with a cellular
+// automaton. Cells beyond the borders and the mask count as walls. Then the
+// region of the start cell is joined with the largest region by the shortest
+// tunnel and the other regions are filled, so only the region connected to
+// the start cell remains. The cave is grown once from the start, because
+// caves of other workers would bring regions which aren't connected to it.
+type Cave struct {
+	fill       float64
+	iterations int
+	rule       CaveRule
+}
+
+// NewCave creates a generator which turns every cell into a wall with the
+// fill chance and smooths the grid the given number of times.
+func NewCave(fill float64, iterations int, rule CaveRule) *Cave {
+	return &Cave{
+		fill:       min(max(fill, 0), 1),
+		iterations: max(iterations, 0),
+		rule:       rule,
+	}
+}
+
+func (c *Cave) singleOrigin() {}
+
+// countWalls returns the number of walls among the 8 cells around the coord.
+func countWalls(grd grid, cells [][]domain.CellType, coord domain.Coord) int {
+	cnt := 0
+
+	for dr := -1; dr <= 1; dr++ {
+		for dc := -1; dc <= 1; dc++ {
+			if dr == 0 && dc == 0 {
+				continue
+			}
+
+			// both topologies are rectangular, so the bounds are checked
+			// directly instead of building the maze data for every cell
+			next := grd.topology.Wrap(domain.NewCoord(coord.Row+dr, coord.Col+dc), grd.height, grd.width)
+			if next.Row < 0 || next.Row >= grd.height || next.Col < 0 || next.Col >= grd.width ||
+				!grd.mask.Allows(next) || cells[next.Row][next.Col] == domain.Wall {
+				cnt++
+			}
+		}
+	}
+
+	return cnt
+}
+
+// smooth applies the rule to every cell at once and draws the changed cells.
+func (c *Cave) smooth(grd grid, cells [][]domain.CellType, rnd *randomSource, drawingChan chan<- cell) {
+	walls := newFlatCells[bool](grd.height, grd.width)
+
+	for i := range grd.height {
+		for j := range grd.width {
+			if coord := domain.NewCoord(i, j); grd.inside(coord) {
+				cnt := countWalls(grd, cells, coord)
+				if grd.cell(cells, coord) == domain.Wall {
+					walls[i][j] = cnt >= c.rule.Survival
+				} else {
+					walls[i][j] = cnt >= c.rule.Birth
+				}
+			}
+		}
+	}
+
+	for i := range grd.height {
+		for j := range grd.width {
+			coord := domain.NewCoord(i, j)
+			if !grd.inside(coord) || walls[i][j] == (grd.cell(cells, coord) == domain.Wall) {
+				continue
+			}
+
+			if walls[i][j] {
+				grd.set(cells, coord, domain.Wall, drawingChan, drawingDelay)
+			} else {
+				grd.set(cells, coord, rnd.cellType(), drawingChan, drawingDelay)
+			}
+		}
+	}
+}
+
+// caveRegions labels cells of every open region with its number starting
+// from 1 and returns the sizes of the regions indexed by their numbers.
+func caveRegions(grd grid, cells [][]domain.CellType) ([][]int, []int) {
+	regions := newFlatCells[int](grd.height, grd.width)
+	sizes := []int{0}
+	queue := make([]domain.Coord, 0)
+
+	var buf [maxNeighbours]domain.Coord
+
+	for i := range grd.height {
+		for j := range grd.width {
+			if cells[i][j] == domain.Wall || regions[i][j] != 0 {
+				continue
+			}
+
+			id := len(sizes)
+			regions[i][j] = id
+			queue = append(queue[:0], domain.NewCoord(i, j))
+
+			for head := 0; head < len(queue); head++ {
+				for _, next := range grd.appendPassageNeighbours(buf[:0], cells, queue[head]) {
+					if regions[next.Row][next.Col] == 0 {
+						regions[next.Row][next.Col] = id
+						queue = append(queue, next)
+					}
+				}
+			}
+
+			sizes = append(sizes, len(queue))
+		}
+	}
+
+	return regions, sizes
+}
+
+// tunnel opens the shortest way from the region to the target one.
+func tunnel(
+	grd grid,
+	cells [][]domain.CellType,
+	regions [][]int,
+	from, to int,
+	rnd *randomSource,
+	drawingChan chan<- cell,
+) {
+	prev := newFlatCells[domain.Coord](grd.height, grd.width)
+	seen := newFlatCells[bool](grd.height, grd.width)
+	queue := make([]domain.Coord, 0)
+
+	for i, row := range regions {
+		for j, id := range row {
+			if id == from {
+				seen[i][j] = true
+				queue = append(queue, domain.NewCoord(i, j))
+			}
+		}
+	}
+
+	var buf [maxNeighbours]domain.Coord
+
+	for head := 0; head < len(queue); head++ {
+		for _, next := range grd.appendNeighbours(buf[:0], queue[head]) {
+			if seen[next.Row][next.Col] {
+				continue
+			}
+
+			seen[next.Row][next.Col] = true
+			prev[next.Row][next.Col] = queue[head]
+
+			if regions[next.Row][next.Col] != to {
+				queue = append(queue, next)
+				continue
+			}
+
+			for cur := prev[next.Row][next.Col]; regions[cur.Row][cur.Col] != from; cur = prev[cur.Row][cur.Col] {
+				if grd.cell(cells, cur) == domain.Wall {
+					grd.set(cells, cur, rnd.cellType(), drawingChan, drawingDelay)
+				}
+			}
+
+			return
+		}
+	}
+}
+
+func (c *Cave) createMazeCellsFromCoord(
+	ctx context.Context,
+	grd grid,
+	start domain.Coord,
+	rnd *randomSource,
+	drawingChan chan<- cell,
+) ([][]domain.CellType, error) {
+	if err := grd.requireSquareFloor(); err != nil {
+		return nil, err
+	}
+
+	cells := grd.newCells()
+
+	for i := range grd.height {
+		for j := range grd.width {
+			if coord := domain.NewCoord(i, j); grd.inside(coord) && rnd.Float64() >= c.fill {
+				grd.set(cells, coord, rnd.cellType(), drawingChan, drawingDelay)
+			}
+		}
+	}
+
+	for range c.iterations {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		c.smooth(grd, cells, rnd, drawingChan)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if grd.cell(cells, start) == domain.Wall {
+		grd.set(cells, start, rnd.cellType(), drawingChan, drawingDelay)
+	}
+
+	regions, sizes := caveRegions(grd, cells)
+
+	largest := 1
+	for id, size := range sizes {
+		if size > sizes[largest] {
+			largest = id
+		}
+	}
+
+	// the tunnel may pass through other regions, so they are labelled again
+	if own := regions[start.Row][start.Col]; own != largest {
+		tunnel(grd, cells, regions, own, largest, rnd, drawingChan)
+		regions, _ = caveRegions(grd, cells)
+	}
+
+	own := regions[start.Row][start.Col]
+
+	for i, row := range regions {
+		for j, id := range row {
+			if id != own && id != 0 {
+				grd.set(cells, domain.NewCoord(i, j), domain.Wall, drawingChan, clearDelay)
+			}
+		}
+	}
+
+	return cells, nil
+}
+
+func caveEntry() registry.Entry[Algorithm] {
+	return registry.Entry[Algorithm]{
+		Name:        "cave",
+		Description: "organic caves grown by a cellular automaton",
+		Params: []registry.Param{
+			registry.FloatParam("fill", "share of walls in the initial noise", DefaultCaveFill, 0, 1),
+			registry.IntParam("iterations", "number of smoothing steps", DefaultCaveIterations, 0),
+			registry.IntParam("birth", "walls of 8 around an open cell which turn it into a wall", DefaultCaveRule().Birth, 0),
+			registry.IntParam("survival", "walls of 8 around a wall which keep it", DefaultCaveRule().Survival, 0),
+		},
+		Supports: supportsSquareFloor,
+		New: func(values registry.Values) Algorithm {
+			return NewCave(
+				values.Float("fill"),
+				values.Int("iterations"),
+				NewCaveRule(values.Int("birth"), values.Int("survival")),
+			)
+		},
+	}
+}
@@ -0,0 +1,65 @@
+package generator
+
+import (
+	"context"
+	"slices"
+
+	"github.com/LLIEPJIOK/mazegenerator/internal/domain"
+)
+
+// checkConstraints returns an error for constraints outside of the maze,
+// walls fixed on start or end and fixed types which aren't terrain.
+func checkConstraints(data domain.MazeData) error {
+	for coord, constraint := range data.Constraints {
+		switch {
+		case !data.Contains(coord),
+			constraint.Kind == domain.FixedWall && (coord == data.Start || coord == data.End),
+			constraint.Kind == domain.FixedTerrain && !slices.Contains(domain.TerrainTypes(), constraint.Terrain):
+			return NewErrInvalidConstraint(coord)
+		}
+	}
+
+	return nil
+}
+
+// applyConstraints carves cells fixed as passages which no partial maze has
+// reached, for example ones walled off by other constraints, and gives cells
+// with fixed terrain their type. Stairs keep their type, so floors stay
+// connected.
+func applyConstraints(
+	ctx context.Context,
+	maze domain.Maze,
+	rnd *randomSource,
+	drawingChan chan<- domain.CellPaintingData,
+	processID int,
+) error {
+	if len(maze.Data.Constraints) == 0 {
+		return nil
+	}
+
+	grd := newGrid(maze.Data)
+
+	// cells are visited in order, so the terrain doesn't depend on the
+	// order of the map
+	for i, row := range maze.Cells {
+		for j, tpe := range row {
+			coord := maze.Data.CoordAt(i, j)
+			if !grd.constraints.Open(coord) || tpe == domain.Stairs {
+				continue
+			}
+
+			fixed, ok := grd.constraints.Terrain(coord)
+			if tpe != domain.Wall && (!ok || tpe == fixed) {
+				continue
+			}
+
+			row[j] = grd.terrain(coord, rnd)
+
+			if err := send(ctx, drawingChan, domain.NewCellPaintingData(i, j, row[j], processID, mergeDelay)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,181 @@
+package generator
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+
+	"github.com/LLIEPJIOK/mazegenerator/internal/domain"
+	"github.com/LLIEPJIOK/mazegenerator/internal/pathfinder"
+)
+
+// DifficultyAttempts is the number of mazes generated before the generator
+// gives up on the difficulty.
+const DifficultyAttempts = 200
+
+// difficultyStream derives seeds of candidate mazes. It's far from the
+// worker streams, so none of the workers shares it.
+const difficultyStream uint64 = math.MaxUint64
+
+// Difficulty is the minimum difficulty of a maze. Zero fields aren't checked.
+type Difficulty struct {
+	// PathCost is the cost of the shortest path from the start to the end.
+	PathCost int
+	// Decisions is the number of cells on the shortest path where another
+	// passage branches off.
+	Decisions int
+	// DeadEnds is the share of passable cells which are dead ends.
+	DeadEnds float64
+}
+
+// ParseDifficulty parses difficulties like "cost 120, decisions 8,
+// dead-ends 0.2". Every part is optional.
+func ParseDifficulty(str string) (Difficulty, error) {
+	var difficulty Difficulty
+
+	for _, part := range strings.Split(str, ",") {
+		fields := strings.Fields(part)
+		if len(fields) != 2 {
+			return Difficulty{}, NewErrInvalidDifficulty(str)
+		}
+
+		value, err := strconv.ParseFloat(fields[1], 64)
+		if err != nil || value < 0 {
+			return Difficulty{}, NewErrInvalidDifficulty(str)
+		}
+
+		switch strings.ToLower(fields[0]) {
+		case "cost":
+			difficulty.PathCost, err = strconv.Atoi(fields[1])
+
+		case "decisions":
+			difficulty.Decisions, err = strconv.Atoi(fields[1])
+
+		case "dead-ends":
+			if value > 1 {
+				return Difficulty{}, NewErrInvalidDifficulty(str)
+			}
+
+			difficulty.DeadEnds = value
+
+		default:
+			return Difficulty{}, NewErrInvalidDifficulty(str)
+		}
+
+		if err != nil {
+			return Difficulty{}, NewErrInvalidDifficulty(str)
+		}
+	}
+
+	return difficulty, nil
+}
+
+func (d Difficulty) String() string {
+	return fmt.Sprintf("cost %d, decisions %d, dead-ends %g", d.PathCost, d.Decisions, d.DeadEnds)
+}
+
+// Met reports whether the score is at least as difficult as every field of d.
+func (d Difficulty) Met(score Difficulty) bool {
+	return score.PathCost >= d.PathCost && score.Decisions >= d.Decisions && score.DeadEnds >= d.DeadEnds
+}
+
+// Measure returns the difficulty of the maze: the shortest path is found by
+// Dijkstra, the start and the end aren't counted as dead ends. A maze without
+// a way from the start to the end has zero path cost and decisions.
+func Measure(ctx context.Context, maze domain.Maze) (Difficulty, error) {
+	path, _, err := pathfinder.NewDijkstra().ShortestPath(ctx, maze, nil)
+	if err != nil {
+		return Difficulty{}, fmt.Errorf("finding shortest path: %w", err)
+	}
+
+	data := maze.Data
+
+	var buf [maxNeighbours]domain.Coord
+
+	exits := func(c domain.Coord) int {
+		cnt := 0
+
+		for _, next := range data.AppendNeighbours(buf[:0], c) {
+			if maze.Passable(c, next) {
+				cnt++
+			}
+		}
+
+		return cnt
+	}
+
+	var score Difficulty
+
+	for i, c := range path[:max(len(path)-1, 0)] {
+		choices := exits(c)
+
+		// the way back to the previous cell isn't a choice
+		if i != 0 {
+			choices--
+		}
+
+		if choices > 1 {
+			score.Decisions++
+		}
+
+		score.PathCost += maze.Cell(path[i+1]).Cost()
+	}
+
+	passable, deadEnds := 0, 0
+
+	for i, row := range maze.Cells {
+		for j, tpe := range row {
+			c := data.CoordAt(i, j)
+			if !tpe.IsTraversable() || !data.Contains(c) || c == data.Start || c == data.End {
+				continue
+			}
+
+			passable++
+
+			if exits(c) == 1 {
+				deadEnds++
+			}
+		}
+	}
+
+	if passable != 0 {
+		score.DeadEnds = float64(deadEnds) / float64(passable)
+	}
+
+	return score, nil
+}
+
+// findDifficultSeed generates mazes without drawing from the seed and seeds
+// derived from it until one of them meets the difficulty and keeps its seed,
+// so the maze is generated again with drawing and can be reproduced.
+func (g *Generator) findDifficultSeed(ctx context.Context, data domain.MazeData) error {
+	seeds := rand.New(rand.NewPCG(g.seed, difficultyStream)) //nolint:gosec // the seed isn't used for security purposes
+
+	candidate := *g
+	candidate.headless = true
+
+	for range DifficultyAttempts {
+		maze, err := candidate.generate(ctx, data, nil)
+		if err != nil {
+			return fmt.Errorf("generating candidate: %w", err)
+		}
+
+		score, err := Measure(ctx, maze)
+		if err != nil {
+			return fmt.Errorf("measuring candidate: %w", err)
+		}
+
+		if g.difficulty.Met(score) {
+			g.seed = candidate.seed
+
+			return nil
+		}
+
+		candidate.seed = seeds.Uint64()
+	}
+
+	return NewErrDifficultyNotMet(g.difficulty, DifficultyAttempts)
+}
@@ -0,0 +1,193 @@
+package generator
+
+import (
+	"context"
+
+	"github.com/LLIEPJIOK/mazegenerator/internal/domain"
+	"github.com/LLIEPJIOK/mazegenerator/internal/registry"
+)
+
+const DefaultMinChamberSize = 1
+
+type chamber struct {
+	top    int
+	left   int
+	bottom int
+	right  int
+}
+
+func newChamber(top, left, bottom, right int) chamber {
+	return chamber{
+		top:    top,
+		left:   left,
+		bottom: bottom,
+		right:  right,
+	}
+}
+
+// RecursiveDivision starts from an open field and splits it into chambers
+// with walls that have a single gap. Walls are placed on cells with the
+// opposite parity to the start cell and gaps on cells with the same parity,
+// so a later wall never closes an earlier gap. The field is divided once
+// from the start, because the union of several divisions leaves hardly any
+// walls.
+type RecursiveDivision struct {
+	minChamberSize int
+}
+
+// NewRecursiveDivision creates a generator which doesn't split chambers into
+// parts narrower than minChamberSize, leaving them as open rooms.
+func NewRecursiveDivision(minChamberSize int) *RecursiveDivision {
+	return &RecursiveDivision{
+		minChamberSize: max(minChamberSize, 1),
+	}
+}
+
+func (d *RecursiveDivision) singleOrigin() {}
+
+// wallPositions returns positions in [from+minChamberSize, to-minChamberSize]
+// whose parity differs from the parity of the start position.
+func (d *RecursiveDivision) wallPositions(from, to, start int) []int {
+	positions := make([]int, 0)
+
+	for i := from + d.minChamberSize; i <= to-d.minChamberSize; i++ {
+		if i%2 != start%2 {
+			positions = append(positions, i)
+		}
+	}
+
+	return positions
+}
+
+// gapPositions returns positions in [from, to] with the parity of the start position.
+func gapPositions(from, to, start int) []int {
+	positions := make([]int, 0)
+
+	for i := from; i <= to; i++ {
+		if i%2 == start%2 {
+			positions = append(positions, i)
+		}
+	}
+
+	return positions
+}
+
+func splitHorizontally(ch chamber, rows, cols []int, rnd *randomSource) bool {
+	switch {
+	case len(cols) == 0:
+		return true
+	case len(rows) == 0:
+		return false
+	case ch.bottom-ch.top > ch.right-ch.left:
+		return true
+	case ch.bottom-ch.top < ch.right-ch.left:
+		return false
+	default:
+		return rnd.IntN(2) == 0
+	}
+}
+
+// split divides the chamber with a wall that has a single gap and returns
+// both parts, or nothing if the chamber is too small to be split.
+func (d *RecursiveDivision) split(
+	cells [][]domain.CellType,
+	ch chamber,
+	start domain.Coord,
+	rnd *randomSource,
+	drawingChan chan<- cell,
+) []chamber {
+	rows := d.wallPositions(ch.top, ch.bottom, start.Row)
+	cols := d.wallPositions(ch.left, ch.right, start.Col)
+
+	if len(rows) == 0 && len(cols) == 0 {
+		return nil
+	}
+
+	if splitHorizontally(ch, rows, cols, rnd) {
+		wallRow := rows[rnd.IntN(len(rows))]
+		gaps := gapPositions(ch.left, ch.right, start.Col)
+		gapCol := gaps[rnd.IntN(len(gaps))]
+
+		for j := ch.left; j <= ch.right; j++ {
+			if j != gapCol {
+				cells[wallRow][j] = domain.Wall
+				draw(drawingChan, newCell(wallRow, j, domain.Wall, drawingDelay))
+			}
+		}
+
+		return []chamber{
+			newChamber(ch.top, ch.left, wallRow-1, ch.right),
+			newChamber(wallRow+1, ch.left, ch.bottom, ch.right),
+		}
+	}
+
+	wallCol := cols[rnd.IntN(len(cols))]
+	gaps := gapPositions(ch.top, ch.bottom, start.Row)
+	gapRow := gaps[rnd.IntN(len(gaps))]
+
+	for i := ch.top; i <= ch.bottom; i++ {
+		if i != gapRow {
+			cells[i][wallCol] = domain.Wall
+			draw(drawingChan, newCell(i, wallCol, domain.Wall, drawingDelay))
+		}
+	}
+
+	return []chamber{
+		newChamber(ch.top, ch.left, ch.bottom, wallCol-1),
+		newChamber(ch.top, wallCol+1, ch.bottom, ch.right),
+	}
+}
+
+func (d *RecursiveDivision) createMazeCellsFromCoord(
+	ctx context.Context,
+	grd grid,
+	start domain.Coord,
+	rnd *randomSource,
+	drawingChan chan<- cell,
+) ([][]domain.CellType, error) {
+	if err := grd.requireBorders(); err != nil {
+		return nil, err
+	}
+
+	if err := grd.requireLattice(); err != nil {
+		return nil, err
+	}
+
+	cells := grd.newCells()
+
+	for i := range grd.height {
+		for j := range grd.width {
+			cells[i][j] = rnd.cellType()
+			draw(drawingChan, newCell(i, j, cells[i][j], drawingDelay))
+		}
+	}
+
+	stack := []chamber{newChamber(0, 0, grd.height-1, grd.width-1)}
+
+	for len(stack) > 0 {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		cur := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		stack = append(stack, d.split(cells, cur, start, rnd, drawingChan)...)
+	}
+
+	return cells, nil
+}
+
+func divisionEntry() registry.Entry[Algorithm] {
+	return registry.Entry[Algorithm]{
+		Name:        "division",
+		Description: "recursive division, splits an open field with walls",
+		Params: []registry.Param{
+			registry.IntParam("chamber-size", "minimum chamber size", DefaultMinChamberSize, 1),
+		},
+		Supports: supportsBorderedLattice,
+		New: func(values registry.Values) Algorithm {
+			return NewRecursiveDivision(values.Int("chamber-size"))
+		},
+	}
+}
@@ -0,0 +1,330 @@
+package generator
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"github.com/LLIEPJIOK/mazegenerator/internal/domain"
+	"github.com/LLIEPJIOK/mazegenerator/internal/registry"
+)
+
+const (
+	DefaultMinRoomSize = 3
+	DefaultMaxRoomSize = 7
+)
+
+const (
+	// themedRoomChance is the chance a room is filled with a single terrain
+	// type, like a money vault or a river hall.
+	themedRoomChance = 0.25
+	// loopChance is the chance adjacent rooms which aren't joined by the
+	// spanning tree get a corridor of their own anyway.
+	loopChance = 0.15
+)
+
+type corridor struct {
+	from int
+	to   int
+}
+
+func newCorridor(from, to int) corridor {
+	return corridor{
+		from: from,
+		to:   to,
+	}
+}
+
+// Dungeon places non-overlapping rectangular rooms separated by walls and
+// joins their centres with corridors along the minimum spanning tree over
+// distances between the centres of adjacent rooms and a few extra edges.
+// The first room always covers the start cell. The dungeon is built once
+// from the start, because rooms of several united dungeons would overlap.
+type Dungeon struct {
+	minRoomSize int
+	maxRoomSize int
+}
+
+// NewDungeon creates a generator which places rooms with sides from
+// minRoomSize to maxRoomSize cells.
+func NewDungeon(minRoomSize, maxRoomSize int) *Dungeon {
+	minRoomSize = max(minRoomSize, 1)
+
+	return &Dungeon{
+		minRoomSize: minRoomSize,
+		maxRoomSize: max(maxRoomSize, minRoomSize),
+	}
+}
+
+func (d *Dungeon) singleOrigin() {}
+
+// roomSide returns a random side of a room which fits into limit cells.
+func (d *Dungeon) roomSide(limit int, rnd *randomSource) int {
+	return min(d.minRoomSize+rnd.IntN(d.maxRoomSize-d.minRoomSize+1), limit)
+}
+
+// startRoom returns a room covering the start cell.
+func (d *Dungeon) startRoom(grd grid, start domain.Coord, rnd *randomSource) chamber {
+	height, width := d.roomSide(grd.height, rnd), d.roomSide(grd.width, rnd)
+	top := min(max(start.Row-rnd.IntN(height), 0), grd.height-height)
+	left := min(max(start.Col-rnd.IntN(width), 0), grd.width-width)
+
+	return newChamber(top, left, top+height-1, left+width-1)
+}
+
+func (d *Dungeon) randomRoom(grd grid, rnd *randomSource) chamber {
+	height, width := d.roomSide(grd.height, rnd), d.roomSide(grd.width, rnd)
+	top, left := rnd.IntN(grd.height-height+1), rnd.IntN(grd.width-width+1)
+
+	return newChamber(top, left, top+height-1, left+width-1)
+}
+
+// fits reports whether the room and the cells around it are walls, so rooms
+// never touch each other.
+func fits(cells [][]domain.CellType, room chamber) bool {
+	for i := max(room.top-1, 0); i <= min(room.bottom+1, len(cells)-1); i++ {
+		for j := max(room.left-1, 0); j <= min(room.right+1, len(cells[i])-1); j++ {
+			if cells[i][j] != domain.Wall {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func centre(room chamber) domain.Coord {
+	return domain.NewCoord((room.top+room.bottom)/2, (room.left+room.right)/2)
+}
+
+// carveRoom fills the room with random terrain or, with themedRoomChance,
+// with a single terrain type.
+func carveRoom(
+	grd grid,
+	cells [][]domain.CellType,
+	room chamber,
+	rnd *randomSource,
+	drawingChan chan<- cell,
+) {
+	themed := rnd.Float64() < themedRoomChance
+	theme := rnd.themeType()
+
+	for i := room.top; i <= room.bottom; i++ {
+		for j := room.left; j <= room.right; j++ {
+			tpe := theme
+			if !themed {
+				tpe = rnd.cellType()
+			}
+
+			grd.set(cells, domain.NewCoord(i, j), tpe, drawingChan, drawingDelay)
+		}
+	}
+}
+
+// carveLine carves walls on the straight line between the cells. Both cells
+// lie in the same row or column.
+func carveLine(
+	grd grid,
+	cells [][]domain.CellType,
+	from, to domain.Coord,
+	rnd *randomSource,
+	drawingChan chan<- cell,
+) {
+	dr, dc := cmp.Compare(to.Row, from.Row), cmp.Compare(to.Col, from.Col)
+
+	for cur := from; ; cur = domain.NewCoord(cur.Row+dr, cur.Col+dc) {
+		if grd.cell(cells, cur) == domain.Wall {
+			grd.set(cells, cur, rnd.cellType(), drawingChan, drawingDelay)
+		}
+
+		if cur == to {
+			return
+		}
+	}
+}
+
+// carveCorridor joins the cells with a corridor which goes along the row
+// first or along the column first. Cells of rooms it passes keep their type.
+// The cells of a column are adjacent on hex grids as well, so the corridor is
+// connected on both lattice topologies.
+func carveCorridor(
+	grd grid,
+	cells [][]domain.CellType,
+	from, to domain.Coord,
+	rnd *randomSource,
+	drawingChan chan<- cell,
+) {
+	corner := domain.NewCoord(from.Row, to.Col)
+	if rnd.IntN(2) == 0 {
+		corner = domain.NewCoord(to.Row, from.Col)
+	}
+
+	carveLine(grd, cells, from, corner, rnd, drawingChan)
+	carveLine(grd, cells, corner, to, rnd, drawingChan)
+}
+
+// roomRegions assigns every cell to the room it's the fewest steps away from.
+func roomRegions(ctx context.Context, grd grid, rooms []chamber) ([][]int, error) {
+	regions := newFlatCells[int](grd.height, grd.width)
+	queue := make([]domain.Coord, 0, grd.height*grd.width)
+
+	for i := range regions {
+		for j := range regions[i] {
+			regions[i][j] = -1
+		}
+	}
+
+	for id, room := range rooms {
+		for i := room.top; i <= room.bottom; i++ {
+			for j := room.left; j <= room.right; j++ {
+				regions[i][j] = id
+				queue = append(queue, domain.NewCoord(i, j))
+			}
+		}
+	}
+
+	var buf [maxNeighbours]domain.Coord
+
+	for head := 0; head < len(queue); head++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		cur := queue[head]
+
+		for _, next := range grd.appendNeighbours(buf[:0], cur) {
+			if regions[next.Row][next.Col] == -1 {
+				regions[next.Row][next.Col] = regions[cur.Row][cur.Col]
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	return regions, nil
+}
+
+// adjacentRooms returns pairs of rooms whose regions touch, the shortest
+// first. They join all rooms, because the regions cover the whole grid.
+func adjacentRooms(grd grid, regions [][]int, centres []domain.Coord) []corridor {
+	pairs := make(map[corridor]struct{})
+
+	var buf [maxNeighbours]domain.Coord
+
+	for i, row := range regions {
+		for j, id := range row {
+			for _, next := range grd.appendNeighbours(buf[:0], domain.NewCoord(i, j)) {
+				if nextID := regions[next.Row][next.Col]; id < nextID {
+					pairs[newCorridor(id, nextID)] = struct{}{}
+				}
+			}
+		}
+	}
+
+	length := func(edge corridor) int {
+		return grd.topology.Distance(centres[edge.from], centres[edge.to], grd.height, grd.width)
+	}
+
+	res := make([]corridor, 0, len(pairs))
+	for pair := range pairs {
+		res = append(res, pair)
+	}
+
+	slices.SortFunc(res, func(first, second corridor) int {
+		return cmp.Or(
+			cmp.Compare(length(first), length(second)),
+			cmp.Compare(first.from, second.from),
+			cmp.Compare(first.to, second.to),
+		)
+	})
+
+	return res
+}
+
+// corridors returns the minimum spanning tree over adjacent rooms built by
+// Kruskal's algorithm and the other adjacent pairs chosen with loopChance,
+// so the dungeon has a few loops.
+func corridors(pairs []corridor, rooms int, rnd *randomSource) []corridor {
+	sets := newUnionFind(rooms)
+	res := make([]corridor, 0, rooms)
+
+	for _, pair := range pairs {
+		if sets.union(pair.from, pair.to) || rnd.Float64() < loopChance {
+			res = append(res, pair)
+		}
+	}
+
+	return res
+}
+
+func (d *Dungeon) createMazeCellsFromCoord(
+	ctx context.Context,
+	grd grid,
+	start domain.Coord,
+	rnd *randomSource,
+	drawingChan chan<- cell,
+) ([][]domain.CellType, error) {
+	if err := grd.requireBorders(); err != nil {
+		return nil, err
+	}
+
+	if err := grd.requireLattice(); err != nil {
+		return nil, err
+	}
+
+	cells := grd.newCells()
+
+	room := d.startRoom(grd, start, rnd)
+	carveRoom(grd, cells, room, rnd, drawingChan)
+
+	rooms := []chamber{room}
+
+	// the number of attempts is enough to pack the grid with rooms densely
+	for range grd.height * grd.width / d.maxRoomSize {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		room := d.randomRoom(grd, rnd)
+		if !fits(cells, room) {
+			continue
+		}
+
+		carveRoom(grd, cells, room, rnd, drawingChan)
+		rooms = append(rooms, room)
+	}
+
+	regions, err := roomRegions(ctx, grd, rooms)
+	if err != nil {
+		return nil, err
+	}
+
+	centres := make([]domain.Coord, len(rooms))
+	for i, room := range rooms {
+		centres[i] = centre(room)
+	}
+
+	for _, edge := range corridors(adjacentRooms(grd, regions, centres), len(rooms), rnd) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		carveCorridor(grd, cells, centres[edge.from], centres[edge.to], rnd, drawingChan)
+	}
+
+	return cells, nil
+}
+
+func dungeonEntry() registry.Entry[Algorithm] {
+	return registry.Entry[Algorithm]{
+		Name:        "dungeon",
+		Description: "rectangular rooms joined by corridors",
+		Params: []registry.Param{
+			registry.IntParam("min-room-size", "minimum room side", DefaultMinRoomSize, 1),
+			registry.IntParam("max-room-size", "maximum room side", DefaultMaxRoomSize, 1),
+		},
+		Supports: supportsBorderedLattice,
+		New: func(values registry.Values) Algorithm {
+			return NewDungeon(values.Int("min-room-size"), values.Int("max-room-size"))
+		},
+	}
+}
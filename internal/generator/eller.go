@@ -0,0 +1,242 @@
+package generator
+
+import (
+	"context"
+
+	"github.com/LLIEPJIOK/mazegenerator/internal/domain"
+	"github.com/LLIEPJIOK/mazegenerator/internal/registry"
+)
+
+// EllerStream produces a maze of fixed width and unbounded height row by row
+// with Eller's algorithm. It keeps only the sets of the current row, so its
+// memory is proportional to the width. Sets are numbered from 1 to the
+// number of rooms in a row and freed numbers are reused, so slices indexed by
+// sets replace maps.
+type EllerStream struct {
+	width     int
+	colOffset int
+	sets      []int
+	rnd       *randomSource
+	// buffers reused by every row, indexed by sets
+	setUsed  []bool
+	setSize  []int
+	setStart []int
+	// buffers reused by every row
+	setOrder []int
+	setRooms []int
+	goesDown []bool
+}
+
+// NewEllerStream creates a stream whose rooms lie in columns with the same
+// parity as colOffset.
+func NewEllerStream(width, colOffset int, seed uint64, terrain domain.TerrainProfile) *EllerStream {
+	return newEllerStream(width, colOffset, newRandomSource(seed, 0, terrain))
+}
+
+func newEllerStream(width, colOffset int, rnd *randomSource) *EllerStream {
+	colOffset %= 2
+	rooms := (width - colOffset + 1) / 2
+
+	return &EllerStream{
+		width:     width,
+		colOffset: colOffset,
+		sets:      make([]int, rooms),
+		rnd:       rnd,
+		setUsed:   make([]bool, rooms+1),
+		setSize:   make([]int, rooms+1),
+		setStart:  make([]int, rooms+1),
+		setOrder:  make([]int, 0, rooms),
+		setRooms:  make([]int, rooms),
+		goesDown:  make([]bool, rooms),
+	}
+}
+
+func (s *EllerStream) col(room int) int {
+	return s.colOffset + 2*room
+}
+
+// fillSets puts rooms which have no set into new sets with the smallest free
+// numbers.
+func (s *EllerStream) fillSets() {
+	clear(s.setUsed)
+
+	for _, set := range s.sets {
+		s.setUsed[set] = true
+	}
+
+	next := 1
+
+	for i := range s.sets {
+		if s.sets[i] != 0 {
+			continue
+		}
+
+		for s.setUsed[next] {
+			next++
+		}
+
+		s.sets[i] = next
+		s.setUsed[next] = true
+	}
+}
+
+func (s *EllerStream) join(from, to int) {
+	for i := range s.sets {
+		if s.sets[i] == from {
+			s.sets[i] = to
+		}
+	}
+}
+
+func (s *EllerStream) roomsRow(joinAll bool) []domain.CellType {
+	s.fillSets()
+
+	row := make([]domain.CellType, s.width)
+
+	for i := range s.sets {
+		row[s.col(i)] = s.rnd.cellType()
+
+		if i == 0 || s.sets[i-1] == s.sets[i] {
+			continue
+		}
+
+		if joinAll || s.rnd.IntN(2) == 0 {
+			s.join(s.sets[i], s.sets[i-1])
+			row[s.col(i)-1] = s.rnd.cellType()
+		}
+	}
+
+	return row
+}
+
+// groupRooms orders sets by their first room in setOrder and lists rooms of
+// every set in setRooms, from setStart to setStart plus setSize.
+func (s *EllerStream) groupRooms() {
+	clear(s.setSize)
+	s.setOrder = s.setOrder[:0]
+
+	for _, set := range s.sets {
+		if s.setSize[set] == 0 {
+			s.setOrder = append(s.setOrder, set)
+		}
+
+		s.setSize[set]++
+	}
+
+	offset := 0
+
+	for _, set := range s.setOrder {
+		s.setStart[set] = offset
+		offset += s.setSize[set]
+		s.setSize[set] = 0
+	}
+
+	for i, set := range s.sets {
+		s.setRooms[s.setStart[set]+s.setSize[set]] = i
+		s.setSize[set]++
+	}
+}
+
+// connectorsRow lets every set go down through at least one of its rooms.
+func (s *EllerStream) connectorsRow() []domain.CellType {
+	row := make([]domain.CellType, s.width)
+
+	s.groupRooms()
+	clear(s.goesDown)
+
+	for _, set := range s.setOrder {
+		rooms := s.setRooms[s.setStart[set] : s.setStart[set]+s.setSize[set]]
+		s.goesDown[rooms[s.rnd.IntN(len(rooms))]] = true
+
+		for _, room := range rooms {
+			if s.rnd.IntN(2) == 0 {
+				s.goesDown[room] = true
+			}
+		}
+	}
+
+	for i := range s.sets {
+		if s.goesDown[i] {
+			row[s.col(i)] = s.rnd.cellType()
+		} else {
+			s.sets[i] = 0
+		}
+	}
+
+	return row
+}
+
+// Next returns the next row of rooms and the row connecting it with the
+// following one.
+func (s *EllerStream) Next() (rooms, connectors []domain.CellType) {
+	rooms = s.roomsRow(false)
+	connectors = s.connectorsRow()
+
+	return rooms, connectors
+}
+
+// Last returns the final row of rooms which joins all remaining sets.
+func (s *EllerStream) Last() []domain.CellType {
+	return s.roomsRow(true)
+}
+
+// Eller builds the maze row by row with an EllerStream. The maze is built
+// once from the start, because united mazes would have loops.
+type Eller struct{}
+
+func NewEller() *Eller {
+	return &Eller{}
+}
+
+func (e *Eller) singleOrigin() {}
+
+func (e *Eller) createMazeCellsFromCoord(
+	ctx context.Context,
+	grd grid,
+	start domain.Coord,
+	rnd *randomSource,
+	drawingChan chan<- cell,
+) ([][]domain.CellType, error) {
+	if err := grd.requireBorders(); err != nil {
+		return nil, err
+	}
+
+	if err := grd.requireLattice(); err != nil {
+		return nil, err
+	}
+
+	cells := grd.newCells()
+
+	drawRow := func(rowID int, row []domain.CellType) {
+		copy(cells[rowID], row)
+
+		for j, tpe := range row {
+			if tpe != domain.Wall {
+				draw(drawingChan, newCell(rowID, j, tpe, drawingDelay))
+			}
+		}
+	}
+
+	stream := newEllerStream(grd.width, start.Col, rnd)
+	lastRoomsRow := grd.height - 1 - (grd.height-1-start.Row%2)%2
+
+	for i := start.Row % 2; i < lastRoomsRow; i += 2 {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		rooms, connectors := stream.Next()
+		drawRow(i, rooms)
+		drawRow(i+1, connectors)
+	}
+
+	drawRow(lastRoomsRow, stream.Last())
+
+	return cells, nil
+}
+
+func ellerEntry() registry.Entry[Algorithm] {
+	return plainEntry("eller", "Eller's algorithm, builds the maze row by row", supportsBorderedLattice, func() Algorithm {
+		return NewEller()
+	})
+}
@@ -0,0 +1,151 @@
+package generator
+
+import (
+	"fmt"
+
+	"github.com/LLIEPJIOK/mazegenerator/internal/domain"
+)
+
+type ErrInvalidStrategy struct {
+	strategy string
+}
+
+func NewErrInvalidStrategy(strategy string) ErrInvalidStrategy {
+	return ErrInvalidStrategy{
+		strategy: strategy,
+	}
+}
+
+func (e ErrInvalidStrategy) Error() string {
+	return fmt.Sprintf("invalid cell selection strategy %q", e.strategy)
+}
+
+type ErrInvalidOrigin struct {
+	origin domain.Coord
+}
+
+func NewErrInvalidOrigin(origin domain.Coord) ErrInvalidOrigin {
+	return ErrInvalidOrigin{
+		origin: origin,
+	}
+}
+
+func (e ErrInvalidOrigin) Error() string {
+	return fmt.Sprintf("origin (%d, %d) is outside of the maze or fixed as a wall", e.origin.Row, e.origin.Col)
+}
+
+type ErrUnsupportedTopology struct {
+	topology domain.Topology
+}
+
+func NewErrUnsupportedTopology(topology domain.Topology) ErrUnsupportedTopology {
+	return ErrUnsupportedTopology{
+		topology: topology,
+	}
+}
+
+func (e ErrUnsupportedTopology) Error() string {
+	return fmt.Sprintf("algorithm doesn't support %s grids", e.topology)
+}
+
+type ErrUnsupportedLevels struct {
+	levels int
+}
+
+func NewErrUnsupportedLevels(levels int) ErrUnsupportedLevels {
+	return ErrUnsupportedLevels{
+		levels: levels,
+	}
+}
+
+func (e ErrUnsupportedLevels) Error() string {
+	return fmt.Sprintf("algorithm doesn't support mazes with %d levels", e.levels)
+}
+
+type ErrOddTorus struct {
+	height int
+	width  int
+}
+
+func NewErrOddTorus(height, width int) ErrOddTorus {
+	return ErrOddTorus{
+		height: height,
+		width:  width,
+	}
+}
+
+func (e ErrOddTorus) Error() string {
+	return fmt.Sprintf("rooms don't tile a %dx%d torus, its height and width must be even", e.height, e.width)
+}
+
+type ErrUnsupportedMask struct{}
+
+func (e ErrUnsupportedMask) Error() string {
+	return "algorithm doesn't support masks"
+}
+
+type ErrInvalidDifficulty struct {
+	difficulty string
+}
+
+func NewErrInvalidDifficulty(difficulty string) ErrInvalidDifficulty {
+	return ErrInvalidDifficulty{
+		difficulty: difficulty,
+	}
+}
+
+func (e ErrInvalidDifficulty) Error() string {
+	return fmt.Sprintf("invalid difficulty %q", e.difficulty)
+}
+
+type ErrDifficultyNotMet struct {
+	difficulty Difficulty
+	attempts   int
+}
+
+func NewErrDifficultyNotMet(difficulty Difficulty, attempts int) ErrDifficultyNotMet {
+	return ErrDifficultyNotMet{
+		difficulty: difficulty,
+		attempts:   attempts,
+	}
+}
+
+func (e ErrDifficultyNotMet) Error() string {
+	return fmt.Sprintf("no maze of %d generated ones has difficulty %s", e.attempts, e.difficulty)
+}
+
+type ErrDisconnectedMask struct{}
+
+func (e ErrDisconnectedMask) Error() string {
+	return "start and end lie on separate islands of the mask"
+}
+
+type ErrUnsupportedConstraints struct{}
+
+func (e ErrUnsupportedConstraints) Error() string {
+	return "algorithm doesn't support constraints"
+}
+
+type ErrInvalidConstraint struct {
+	coord domain.Coord
+}
+
+func NewErrInvalidConstraint(coord domain.Coord) ErrInvalidConstraint {
+	return ErrInvalidConstraint{
+		coord: coord,
+	}
+}
+
+func (e ErrInvalidConstraint) Error() string {
+	return fmt.Sprintf(
+		"constraint (%d, %d) is outside of the maze, walls up start or end or fixes a type which isn't terrain",
+		e.coord.Row,
+		e.coord.Col,
+	)
+}
+
+type ErrWalledOff struct{}
+
+func (e ErrWalledOff) Error() string {
+	return "walls fixed by constraints separate start and end"
+}
@@ -1,9 +1,9 @@
 package generator
 
 import (
-	"crypto/rand"
+	"context"
 	"fmt"
-	"math/big"
+	"math/rand/v2"
 	"sync"
 	"time"
 
@@ -33,129 +33,215 @@ func newCell(row, col int, tpe domain.CellType, delay time.Duration) cell {
 	}
 }
 
+// Algorithm grows a partial maze from the start coordinate. It stops and
+// returns the context error as soon as ctx is done.
 type Algorithm interface {
 	createMazeCellsFromCoord(
-		height, width int,
+		ctx context.Context,
+		grd grid,
 		start domain.Coord,
+		rnd *randomSource,
 		drawingChan chan<- cell,
 	) ([][]domain.CellType, error)
 }
 
-type Generator struct {
-	algo Algorithm
-	dir  domain.Direction
+// singleOrigin is implemented by algorithms which build the whole maze from
+// the start at once. Uniting several of their mazes would break their
+// structure, so they get no other origins.
+type singleOrigin interface {
+	singleOrigin()
 }
 
-func New(algo Algorithm) *Generator {
-	return &Generator{
-		algo: algo,
-		dir:  domain.DefaultDirection(),
+// Random streams used by GenerateMaze. Every goroutine gets its own stream
+// derived from the generator seed, so the result doesn't depend on scheduling.
+// Worker i uses the workerStream+i stream.
+const (
+	mergeStream uint64 = iota
+	biomeStream
+	originStream
+	repairStream
+	workerStream
+)
+
+// DefaultWorkers is the number of partial mazes: one grows from the start
+// and one from the end.
+const DefaultWorkers = 2
+
+type Option func(*Generator)
+
+// WithTerrain sets weights of cell types placed into passages.
+func WithTerrain(terrain domain.TerrainProfile) Option {
+	return func(g *Generator) {
+		g.terrain = terrain
 	}
 }
 
-func (g *Generator) clearDeadEnd(
-	cells [][]domain.CellType,
-	height, width int,
-	start domain.Coord,
-	drawingChan chan<- cell,
-) [][]domain.CellType {
-	newCells := make([][]domain.CellType, height)
-
-	for i := range height {
-		newCells[i] = make([]domain.CellType, width)
+// WithBiomes enables the final stage which groups terrain into coherent
+// biomes: rivers form bands and sand and money form patches.
+func WithBiomes(enabled bool) Option {
+	return func(g *Generator) {
+		g.biomes = enabled
+	}
+}
 
-		for j := range width {
-			cntPassages := 0
+// WithBraid sets the share of dead ends of the merged maze turned into loops,
+// from 0, which keeps the maze as it's generated, to 1 for a maze without dead
+// ends. The other dead ends are left as they are.
+func WithBraid(braid float64) Option {
+	return func(g *Generator) {
+		g.braidFactor = min(max(braid, 0), 1)
+	}
+}
 
-			for k := range g.dir.Rows {
-				newRowID, newColID := i+g.dir.Rows[k], j+g.dir.Cols[k]
-				if min(newRowID, newColID) < 0 || newRowID >= height || newColID >= width {
-					continue
-				}
+// WithOrigins adds coordinates partial mazes grow from. Start and end are
+// always used as the first two origins.
+func WithOrigins(origins ...domain.Coord) Option {
+	return func(g *Generator) {
+		g.extraOrigins = append(g.extraOrigins, origins...)
+	}
+}
 
-				if cells[newRowID][newColID] != domain.Wall {
-					cntPassages++
-				}
-			}
+// WithWorkers sets the number of partial mazes generated and merged. Origins
+// which aren't set explicitly are placed automatically, as far as possible
+// from each other.
+func WithWorkers(workers int) Option {
+	return func(g *Generator) {
+		g.workers = max(workers, DefaultWorkers)
+	}
+}
 
-			if cntPassages == 1 && (i != start.Row || j != start.Col) {
-				newCells[i][j] = domain.Wall
-				drawingChan <- newCell(i, j, domain.Wall, clearDelay)
-			} else {
-				newCells[i][j] = cells[i][j]
-			}
+// WithConcurrency limits the number of partial mazes generated at the same
+// time. A non-positive limit means no limit.
+func WithConcurrency(limit int) Option {
+	return func(g *Generator) {
+		if limit <= 0 {
+			limit = -1
 		}
+
+		g.concurrency = limit
 	}
+}
 
-	return newCells
+// WithMerge sets how conflicts between partial mazes are settled.
+func WithMerge(strategy MergeStrategy) Option {
+	return func(g *Generator) {
+		g.merge = strategy
+	}
 }
 
-const clearDeadEndNumber = 10
+// WithHeadless disables drawing: GenerateMaze sends nothing to the painting
+// channel, which may be nil, and partial mazes are generated without
+// drawing events, so huge mazes are generated as fast as possible.
+func WithHeadless(enabled bool) Option {
+	return func(g *Generator) {
+		g.headless = enabled
+	}
+}
+
+// WithDifficulty makes GenerateMaze regenerate the maze with seeds derived
+// from the generator seed until it meets the difficulty. The seed of the
+// accepted maze is returned by Seed.
+func WithDifficulty(difficulty Difficulty) Option {
+	return func(g *Generator) {
+		g.difficulty = difficulty
+	}
+}
+
+// WithSeed makes the generator reproducible: the same seed, maze data and
+// algorithm always produce the same maze.
+func WithSeed(seed uint64) Option {
+	return func(g *Generator) {
+		g.seed = seed
+	}
+}
+
+type Generator struct {
+	algo         Algorithm
+	seed         uint64
+	terrain      domain.TerrainProfile
+	biomes       bool
+	braidFactor  float64
+	extraOrigins []domain.Coord
+	workers      int
+	concurrency  int
+	merge        MergeStrategy
+	headless     bool
+	difficulty   Difficulty
+}
+
+func New(algo Algorithm, opts ...Option) *Generator {
+	gen := &Generator{
+		algo:        algo,
+		seed:        rand.Uint64(), //nolint:gosec // the seed isn't used for security purposes
+		terrain:     domain.DefaultTerrain(),
+		braidFactor: DefaultBraid,
+		workers:     DefaultWorkers,
+		concurrency: -1,
+	}
+
+	for _, opt := range opts {
+		opt(gen)
+	}
+
+	return gen
+}
+
+func (g *Generator) Seed() uint64 {
+	return g.seed
+}
 
 func (g *Generator) generateMazeCellsFromCoord(
-	height, width int,
+	ctx context.Context,
+	grd grid,
 	start domain.Coord,
+	rnd *randomSource,
 	drawingChan chan<- cell,
 ) ([][]domain.CellType, error) {
-	cells, err := g.algo.createMazeCellsFromCoord(height, width, start, drawingChan)
+	cells, err := g.algo.createMazeCellsFromCoord(ctx, grd, start, rnd, drawingChan)
 	if err != nil {
 		return nil, fmt.Errorf(
 			"algorithm.createMazeFromCoord(%d, %d, %#v): %w",
-			height,
-			width,
+			grd.height,
+			grd.width,
 			start,
 			err,
 		)
 	}
 
-	// minimum is needed so as not to remove all the cells if the sizes are small
-	for range min(clearDeadEndNumber, min(height, width)-1) {
-		cells = g.clearDeadEnd(cells, height, width, start, drawingChan)
-	}
-
 	return cells, nil
 }
 
-func mergeMazes(first, second domain.Maze, drawingChan chan<- domain.CellPaintingData,
-	processID int,
-) (domain.Maze, error) {
-	height, width := first.Data.Height, first.Data.Width
-	mergedCells := make([][]domain.CellType, height)
-
-	for i := range height {
-		mergedCells[i] = make([]domain.CellType, width)
-
-		for j := range width {
-			switch {
-			case first.Cells[i][j] == domain.Wall:
-				mergedCells[i][j] = second.Cells[i][j]
-			case second.Cells[i][j] == domain.Wall:
-				mergedCells[i][j] = first.Cells[i][j]
-			default:
-				numb, err := rand.Int(rand.Reader, big.NewInt(2))
-				if err != nil {
-					return domain.Maze{}, fmt.Errorf("generate random number: %w", err)
-				}
-
-				if numb.Int64() == 0 {
-					mergedCells[i][j] = first.Cells[i][j]
-				} else {
-					mergedCells[i][j] = second.Cells[i][j]
-				}
-			}
+func cellToPaintingData(c cell, id int) domain.CellPaintingData {
+	return domain.NewCellPaintingData(c.Row, c.Col, c.Tpe, id, c.Delay)
+}
 
-			drawingChan <- domain.NewCellPaintingData(i, j, mergedCells[i][j], processID, mergeDelay)
-		}
+// draw passes the cell to drawingChan. A nil channel means the maze is
+// generated without drawing.
+func draw(drawingChan chan<- cell, c cell) {
+	if drawingChan != nil {
+		drawingChan <- c
 	}
-
-	return domain.NewMaze(first.Data, mergedCells), nil
 }
 
-func cellToPaintingData(c cell, id int) domain.CellPaintingData {
-	return domain.NewCellPaintingData(c.Row, c.Col, c.Tpe, id, c.Delay)
+// send passes data to paintingChan unless ctx is done first. A nil channel
+// means the maze is generated without drawing.
+func send(ctx context.Context, paintingChan chan<- domain.CellPaintingData, data domain.CellPaintingData) error {
+	if paintingChan == nil {
+		return ctx.Err()
+	}
+
+	select {
+	case paintingChan <- data:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
 
-func mergeChannels(out chan<- domain.CellPaintingData, in ...chan cell) {
+// mergeChannels forwards cells of every input channel to out until the
+// channels are closed. Once ctx is done the cells are dropped, so senders
+// never block on a painter which has stopped reading.
+func mergeChannels(ctx context.Context, out chan<- domain.CellPaintingData, in ...chan cell) {
 	wg := &sync.WaitGroup{}
 
 	for i, ch := range in {
@@ -164,85 +250,169 @@ func mergeChannels(out chan<- domain.CellPaintingData, in ...chan cell) {
 		go func(id int) {
 			defer wg.Done()
 
-			for data := range ch {
-				out <- cellToPaintingData(data, id)
-			}
+			forward(ctx, out, ch, id)
 		}(i + 1)
 	}
 
 	wg.Wait()
 }
 
+// forward passes cells of the input channel to out with the sender ID until
+// the channel is closed. Once ctx is done the cells are dropped.
+func forward(ctx context.Context, out chan<- domain.CellPaintingData, in <-chan cell, id int) {
+	for data := range in {
+		_ = send(ctx, out, cellToPaintingData(data, id))
+	}
+}
+
+// GenerateMaze grows a partial maze from every origin concurrently and
+// merges them. Cells fixed by the constraints of the maze data keep their
+// walls, passages and terrain. If the end isn't reachable from the start after merging, the
+// fewest walls needed to connect them are opened and listed in Maze.Repaired.
+// Cells of every partial maze are sent to paintingChan with
+// SenderID equal to the worker number starting from 1, merged cells are
+// sent with SenderID 0. Generation stops as soon as ctx is done and the
+// context error is returned.
 func (g *Generator) GenerateMaze(
+	ctx context.Context,
 	data domain.MazeData,
 	paintingChan chan<- domain.CellPaintingData,
 ) (domain.Maze, error) {
-	eg := &errgroup.Group{}
+	if g.difficulty != (Difficulty{}) {
+		if err := g.findDifficultSeed(ctx, data); err != nil {
+			return domain.Maze{}, fmt.Errorf("finding difficult maze: %w", err)
+		}
+	}
 
-	var startMaze, endMaze domain.Maze
+	return g.generate(ctx, data, paintingChan)
+}
 
-	ch1, ch2 := make(chan cell), make(chan cell)
+func (g *Generator) generate(
+	ctx context.Context,
+	data domain.MazeData,
+	paintingChan chan<- domain.CellPaintingData,
+) (domain.Maze, error) {
+	if err := checkConstraints(data); err != nil {
+		return domain.Maze{}, fmt.Errorf("checking constraints: %w", err)
+	}
 
-	eg.Go(func() error {
-		defer close(ch1)
+	origins, err := g.origins(data, newRandomSource(g.seed, originStream, g.terrain))
+	if err != nil {
+		return domain.Maze{}, fmt.Errorf("placing origins: %w", err)
+	}
 
-		cells, err := g.generateMazeCellsFromCoord(data.Height, data.Width, data.Start, ch1)
-		if err != nil {
-			return fmt.Errorf("generating maze from coord: %w", err)
-		}
+	if g.headless {
+		paintingChan = nil
+	}
 
-		startMaze = domain.NewMaze(data, cells)
+	partials, err := g.generatePartials(ctx, data, origins, paintingChan)
+	if err != nil {
+		return domain.Maze{}, err
+	}
 
-		return nil
-	})
+	maze, err := newMerger(g.merge, partials, origins, newRandomSource(g.seed, mergeStream, g.terrain)).
+		mergeMazes(ctx, paintingChan, 0)
+	if err != nil {
+		return domain.Maze{}, fmt.Errorf("merging mazes: %w", err)
+	}
 
-	eg.Go(func() error {
-		defer close(ch2)
+	return g.finish(ctx, maze, paintingChan)
+}
 
-		cells, err := g.generateMazeCellsFromCoord(data.Height, data.Width, data.End, ch2)
-		if err != nil {
-			return fmt.Errorf("generating maze from coord: %w", err)
+// generatePartials grows a partial maze from every origin concurrently.
+// Cells of the i-th worker are sent to paintingChan with SenderID i+1.
+func (g *Generator) generatePartials(
+	ctx context.Context,
+	data domain.MazeData,
+	origins []domain.Coord,
+	paintingChan chan<- domain.CellPaintingData,
+) ([]domain.Maze, error) {
+	partials := make([]domain.Maze, len(origins))
+	channels := make([]chan cell, len(origins))
+	merged := make(chan struct{})
+
+	if paintingChan != nil {
+		for i := range channels {
+			channels[i] = make(chan cell)
 		}
 
-		endMaze = domain.NewMaze(data, cells)
+		go func() {
+			defer close(merged)
 
-		return nil
-	})
+			mergeChannels(ctx, paintingChan, channels...)
+		}()
+	} else {
+		close(merged)
+	}
 
-	eg.Go(func() error {
-		mergeChannels(paintingChan, ch1, ch2)
-		return nil
-	})
+	eg, egCtx := errgroup.WithContext(ctx)
+	eg.SetLimit(g.concurrency)
 
-	if err := eg.Wait(); err != nil {
-		return domain.Maze{}, fmt.Errorf("errgroup: %w", err)
+	for i, origin := range origins {
+		eg.Go(func() error {
+			if channels[i] != nil {
+				defer close(channels[i])
+			}
+
+			cells, err := g.generateMazeCellsFromCoord(
+				egCtx,
+				newGrid(data),
+				origin,
+				newRandomSource(g.seed, workerStream+uint64(i), g.terrain),
+				channels[i],
+			)
+			if err != nil {
+				return fmt.Errorf("generating maze from coord: %w", err)
+			}
+
+			partials[i] = domain.NewMaze(data, cells)
+
+			return nil
+		})
 	}
 
-	maze, err := mergeMazes(startMaze, endMaze, paintingChan, 0)
+	err := eg.Wait()
+	<-merged
+
 	if err != nil {
-		return domain.Maze{}, fmt.Errorf("merge mazes: %w", err)
+		return nil, fmt.Errorf("errgroup: %w", err)
 	}
 
-	return maze, nil
+	return partials, nil
 }
 
-func randomCellType() (domain.CellType, error) {
-	numb, err := rand.Int(rand.Reader, big.NewInt(15))
-	if err != nil {
-		return domain.Wall, fmt.Errorf("generate random number: %w", err)
+// finish carves the constraints into the merged maze, connects its start
+// and end and paints biomes.
+func (g *Generator) finish(
+	ctx context.Context,
+	maze domain.Maze,
+	paintingChan chan<- domain.CellPaintingData,
+) (domain.Maze, error) {
+	// constraints and braiding share the stream of the repair, so mazes
+	// without them don't change
+	repairRnd := newRandomSource(g.seed, repairStream, g.terrain)
+
+	if err := applyConstraints(ctx, maze, repairRnd, paintingChan, 0); err != nil {
+		return domain.Maze{}, fmt.Errorf("applying constraints: %w", err)
 	}
 
-	switch numb.Int64() {
-	case 0:
-		return domain.Money, nil
+	repaired, err := repair(ctx, maze, repairRnd, paintingChan, 0)
+	if err != nil {
+		return domain.Maze{}, fmt.Errorf("repairing maze: %w", err)
+	}
 
-	case 1:
-		return domain.River, nil
+	maze.Repaired = repaired
 
-	case 2:
-		return domain.Sand, nil
+	if err := g.braid(ctx, maze, repairRnd, paintingChan); err != nil {
+		return domain.Maze{}, fmt.Errorf("braid: %w", err)
+	}
 
-	default:
-		return domain.Passage, nil
+	if g.biomes {
+		maze, err = paintBiomes(ctx, maze, newRandomSource(g.seed, biomeStream, g.terrain), paintingChan, 0)
+		if err != nil {
+			return domain.Maze{}, fmt.Errorf("painting biomes: %w", err)
+		}
 	}
+
+	return maze, nil
 }
@@ -1,8 +1,11 @@
 package generator_test
 
 import (
+	"context"
 	"fmt"
+	"slices"
 	"testing"
+	"time"
 
 	"github.com/LLIEPJIOK/mazegenerator/internal/domain"
 	"github.com/LLIEPJIOK/mazegenerator/internal/generator"
@@ -62,6 +65,104 @@ func TestGenerateMaze(t *testing.T) {
 			algorithm: generator.NewBacktrack(),
 			ch:        make(chan domain.CellPaintingData),
 		},
+		{
+			data:      domain.NewMazeData(9, 9, domain.NewCoord(0, 0), domain.NewCoord(8, 8)),
+			algorithm: generator.NewKruskal(),
+			ch:        make(chan domain.CellPaintingData),
+		},
+		{
+			data:      domain.NewMazeData(10, 13, domain.NewCoord(0, 5), domain.NewCoord(9, 0)),
+			algorithm: generator.NewKruskal(),
+			ch:        make(chan domain.CellPaintingData),
+		},
+		{
+			data:      domain.NewMazeData(2, 2, domain.NewCoord(0, 0), domain.NewCoord(1, 1)),
+			algorithm: generator.NewKruskal(),
+			ch:        make(chan domain.CellPaintingData),
+		},
+		{
+			data:      domain.NewMazeData(11, 11, domain.NewCoord(0, 0), domain.NewCoord(10, 10)),
+			algorithm: generator.NewWilson(),
+			ch:        make(chan domain.CellPaintingData),
+		},
+		{
+			data:      domain.NewMazeData(8, 14, domain.NewCoord(7, 3), domain.NewCoord(0, 13)),
+			algorithm: generator.NewWilson(),
+			ch:        make(chan domain.CellPaintingData),
+		},
+		{
+			data:      domain.NewMazeData(12, 9, domain.NewCoord(0, 4), domain.NewCoord(11, 8)),
+			algorithm: generator.NewEller(),
+			ch:        make(chan domain.CellPaintingData),
+		},
+		{
+			data:      domain.NewMazeData(2, 3, domain.NewCoord(1, 0), domain.NewCoord(0, 2)),
+			algorithm: generator.NewEller(),
+			ch:        make(chan domain.CellPaintingData),
+		},
+		{
+			data:      domain.NewMazeData(14, 20, domain.NewCoord(0, 0), domain.NewCoord(13, 19)),
+			algorithm: generator.NewRecursiveDivision(generator.DefaultMinChamberSize),
+			ch:        make(chan domain.CellPaintingData),
+		},
+		{
+			data:      domain.NewMazeData(15, 15, domain.NewCoord(0, 7), domain.NewCoord(14, 3)),
+			algorithm: generator.NewRecursiveDivision(4),
+			ch:        make(chan domain.CellPaintingData),
+		},
+		{
+			data:      domain.NewMazeData(13, 13, domain.NewCoord(0, 0), domain.NewCoord(12, 12)),
+			algorithm: generator.NewHuntAndKill(),
+			ch:        make(chan domain.CellPaintingData),
+		},
+		{
+			data:      domain.NewMazeData(10, 7, domain.NewCoord(9, 1), domain.NewCoord(0, 6)),
+			algorithm: generator.NewHuntAndKill(),
+			ch:        make(chan domain.CellPaintingData),
+		},
+		{
+			data:      domain.NewMazeData(11, 15, domain.NewCoord(0, 0), domain.NewCoord(10, 14)),
+			algorithm: generator.NewAldousBroder(),
+			ch:        make(chan domain.CellPaintingData),
+		},
+		{
+			data:      domain.NewMazeData(6, 9, domain.NewCoord(5, 4), domain.NewCoord(0, 3)),
+			algorithm: generator.NewAldousBroder(),
+			ch:        make(chan domain.CellPaintingData),
+		},
+		{
+			data:      domain.NewMazeData(12, 16, domain.NewCoord(0, 0), domain.NewCoord(11, 15)),
+			algorithm: generator.NewGrowingTree(nil),
+			ch:        make(chan domain.CellPaintingData),
+		},
+		{
+			data: domain.NewMazeData(13, 9, domain.NewCoord(12, 2), domain.NewCoord(0, 8)),
+			algorithm: generator.NewGrowingTree(generator.Strategy{
+				generator.NewWeightedSelection(generator.SelectOldest, 1),
+				generator.NewWeightedSelection(generator.SelectRandom, 3),
+			}),
+			ch: make(chan domain.CellPaintingData),
+		},
+		{
+			data:      domain.NewMazeData(20, 25, domain.NewCoord(0, 0), domain.NewCoord(19, 24)),
+			algorithm: generator.NewDungeon(generator.DefaultMinRoomSize, generator.DefaultMaxRoomSize),
+			ch:        make(chan domain.CellPaintingData),
+		},
+		{
+			data:      domain.NewMazeData(3, 4, domain.NewCoord(2, 0), domain.NewCoord(0, 3)),
+			algorithm: generator.NewDungeon(2, 5),
+			ch:        make(chan domain.CellPaintingData),
+		},
+		{
+			data:      domain.NewMazeData(20, 25, domain.NewCoord(0, 0), domain.NewCoord(19, 24)),
+			algorithm: defaultCave(),
+			ch:        make(chan domain.CellPaintingData),
+		},
+		{
+			data:      domain.NewMazeData(8, 6, domain.NewCoord(7, 5), domain.NewCoord(0, 0)),
+			algorithm: generator.NewCave(0.6, 2, generator.NewCaveRule(6, 3)),
+			ch:        make(chan domain.CellPaintingData),
+		},
 	}
 
 	for i, testCase := range testCases {
@@ -77,6 +178,7 @@ func TestGenerateMaze(t *testing.T) {
 			go func() {
 				defer close(testCase.ch)
 				maze, err = gen.GenerateMaze(
+					context.Background(),
 					testCase.data,
 					testCase.ch,
 				)
@@ -96,3 +198,2371 @@ func TestGenerateMaze(t *testing.T) {
 		})
 	}
 }
+
+func generateWithDiscard(t *testing.T, gen *generator.Generator, data domain.MazeData) domain.Maze {
+	t.Helper()
+
+	ch := make(chan domain.CellPaintingData)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		for range ch {
+		}
+	}()
+
+	maze, err := gen.GenerateMaze(context.Background(), data, ch)
+	close(ch)
+	<-done
+
+	require.NoError(t, err, "generate maze should return nil error")
+
+	return maze
+}
+
+func TestGenerateMazeWithSeed(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		data      domain.MazeData
+		algorithm func() generator.Algorithm
+		seed      uint64
+	}{
+		{
+			data:      domain.NewMazeData(10, 10, domain.NewCoord(0, 0), domain.NewCoord(9, 9)),
+			algorithm: func() generator.Algorithm { return generator.NewPrim() },
+			seed:      1,
+		},
+		{
+			data:      domain.NewMazeData(15, 10, domain.NewCoord(14, 0), domain.NewCoord(14, 9)),
+			algorithm: func() generator.Algorithm { return generator.NewBacktrack() },
+			seed:      42,
+		},
+		{
+			data:      domain.NewMazeData(20, 30, domain.NewCoord(0, 15), domain.NewCoord(19, 0)),
+			algorithm: func() generator.Algorithm { return generator.NewPrim() },
+			seed:      1 << 40,
+		},
+		{
+			data:      domain.NewMazeData(17, 11, domain.NewCoord(0, 3), domain.NewCoord(16, 10)),
+			algorithm: func() generator.Algorithm { return generator.NewKruskal() },
+			seed:      7,
+		},
+		{
+			data:      domain.NewMazeData(13, 9, domain.NewCoord(12, 4), domain.NewCoord(0, 0)),
+			algorithm: func() generator.Algorithm { return generator.NewWilson() },
+			seed:      2024,
+		},
+		{
+			data:      domain.NewMazeData(16, 16, domain.NewCoord(15, 1), domain.NewCoord(0, 15)),
+			algorithm: func() generator.Algorithm { return generator.NewEller() },
+			seed:      99,
+		},
+		{
+			data:      domain.NewMazeData(18, 12, domain.NewCoord(5, 0), domain.NewCoord(17, 11)),
+			algorithm: func() generator.Algorithm { return generator.NewRecursiveDivision(3) },
+			seed:      3,
+		},
+		{
+			data:      domain.NewMazeData(14, 14, domain.NewCoord(0, 13), domain.NewCoord(13, 0)),
+			algorithm: func() generator.Algorithm { return generator.NewHuntAndKill() },
+			seed:      5,
+		},
+		{
+			data:      domain.NewMazeData(12, 12, domain.NewCoord(11, 6), domain.NewCoord(0, 6)),
+			algorithm: func() generator.Algorithm { return generator.NewAldousBroder() },
+			seed:      6,
+		},
+		{
+			data: domain.NewMazeData(15, 15, domain.NewCoord(0, 0), domain.NewCoord(14, 14)),
+			algorithm: func() generator.Algorithm {
+				return generator.NewGrowingTree(generator.Strategy{
+					generator.NewWeightedSelection(generator.SelectNewest, 75),
+					generator.NewWeightedSelection(generator.SelectRandom, 25),
+				})
+			},
+			seed: 8,
+		},
+		{
+			data:      domain.NewMazeData(30, 40, domain.NewCoord(29, 0), domain.NewCoord(0, 39)),
+			algorithm: func() generator.Algorithm { return generator.NewDungeon(2, 6) },
+			seed:      9,
+		},
+		{
+			data: domain.NewMazeData(25, 35, domain.NewCoord(0, 0), domain.NewCoord(24, 34)),
+			algorithm: func() generator.Algorithm {
+				return defaultCave()
+			},
+			seed: 10,
+		},
+	}
+
+	for i, testCase := range testCases {
+		t.Run(fmt.Sprintf("#%d", i+1), func(t *testing.T) {
+			t.Parallel()
+
+			first := generateWithDiscard(
+				t,
+				generator.New(testCase.algorithm(), generator.WithSeed(testCase.seed)),
+				testCase.data,
+			)
+			second := generateWithDiscard(
+				t,
+				generator.New(testCase.algorithm(), generator.WithSeed(testCase.seed)),
+				testCase.data,
+			)
+
+			require.Equal(t, first, second, "mazes with the same seed should be equal")
+		})
+	}
+}
+
+func countReachable(cells [][]domain.CellType, start domain.Coord) int {
+	dir := domain.DefaultDirection()
+	visited := map[domain.Coord]struct{}{start: {}}
+	queue := []domain.Coord{start}
+
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+
+		for i := range dir.Rows {
+			next := domain.NewCoord(cur.Row+dir.Rows[i], cur.Col+dir.Cols[i])
+			if min(next.Row, next.Col) < 0 || next.Row >= len(cells) || next.Col >= len(cells[next.Row]) ||
+				cells[next.Row][next.Col] == domain.Wall {
+				continue
+			}
+
+			if _, ok := visited[next]; !ok {
+				visited[next] = struct{}{}
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	return len(visited)
+}
+
+// requirePerfect checks that passages of a square lattice maze form a
+// spanning tree over its rooms: every room is reached and there are no loops.
+func requirePerfect(t *testing.T, maze domain.Maze) {
+	t.Helper()
+
+	start := maze.Data.Start
+	rooms, passages, joints := 0, 0, 0
+
+	for i, row := range maze.Cells {
+		for j, tpe := range row {
+			if i%2 == start.Row%2 && j%2 == start.Col%2 {
+				rooms++
+			}
+
+			if tpe == domain.Wall {
+				continue
+			}
+
+			passages++
+
+			if i+1 < len(maze.Cells) && maze.Cells[i+1][j] != domain.Wall {
+				joints++
+			}
+
+			if j+1 < len(row) && row[j+1] != domain.Wall {
+				joints++
+			}
+		}
+	}
+
+	require.Equal(t, 2*rooms-1, passages, "rooms and walls between them should be carved")
+	require.Equal(t, passages, countReachable(maze.Cells, start), "all passages should be reachable")
+	require.Equal(t, passages-1, joints, "maze shouldn't have loops")
+}
+
+func TestGeneratePerfectMaze(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		data      domain.MazeData
+		algorithm generator.Algorithm
+	}{
+		{
+			data:      domain.NewMazeData(11, 31, domain.NewCoord(0, 0), domain.NewCoord(10, 30)),
+			algorithm: generator.NewWilson(),
+		},
+		{
+			data:      domain.NewMazeData(20, 15, domain.NewCoord(19, 7), domain.NewCoord(1, 1)),
+			algorithm: generator.NewWilson(),
+		},
+		{
+			data:      domain.NewMazeData(11, 31, domain.NewCoord(0, 0), domain.NewCoord(10, 30)),
+			algorithm: generator.NewAldousBroder(),
+		},
+		{
+			data:      domain.NewMazeData(11, 31, domain.NewCoord(0, 0), domain.NewCoord(10, 30)),
+			algorithm: generator.NewKruskal(),
+		},
+		{
+			data:      domain.NewMazeData(25, 9, domain.NewCoord(12, 4), domain.NewCoord(24, 0)),
+			algorithm: generator.NewKruskal(),
+		},
+		{
+			data:      domain.NewMazeData(11, 31, domain.NewCoord(0, 0), domain.NewCoord(10, 30)),
+			algorithm: generator.NewEller(),
+		},
+		{
+			data:      domain.NewMazeData(16, 13, domain.NewCoord(15, 5), domain.NewCoord(1, 11)),
+			algorithm: generator.NewEller(),
+		},
+		{
+			data:      domain.NewMazeData(11, 31, domain.NewCoord(0, 0), domain.NewCoord(10, 30)),
+			algorithm: generator.NewHuntAndKill(),
+		},
+		{
+			data:      domain.NewMazeData(13, 22, domain.NewCoord(6, 21), domain.NewCoord(12, 1)),
+			algorithm: generator.NewHuntAndKill(),
+		},
+		{
+			data:      domain.NewMazeData(15, 16, domain.NewCoord(7, 15), domain.NewCoord(13, 1)),
+			algorithm: generator.NewAldousBroder(),
+		},
+		{
+			data:      domain.NewMazeData(11, 31, domain.NewCoord(0, 0), domain.NewCoord(10, 30)),
+			algorithm: generator.NewGrowingTree(nil),
+		},
+		{
+			data: domain.NewMazeData(17, 12, domain.NewCoord(8, 11), domain.NewCoord(16, 1)),
+			algorithm: generator.NewGrowingTree(generator.Strategy{
+				generator.NewWeightedSelection(generator.SelectOldest, 1),
+				generator.NewWeightedSelection(generator.SelectRandom, 3),
+			}),
+		},
+	}
+
+	for i, testCase := range testCases {
+		t.Run(fmt.Sprintf("#%d", i+1), func(t *testing.T) {
+			t.Parallel()
+
+			maze := generateWithDiscard(
+				t,
+				generator.New(testCase.algorithm, generator.WithSeed(uint64(i)), generator.WithBraid(0)),
+				testCase.data,
+			)
+
+			requirePerfect(t, maze)
+		})
+	}
+}
+
+func TestEllerStream(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		width     int
+		colOffset int
+		roomRows  int
+	}{
+		{width: 9, colOffset: 0, roomRows: 50},
+		{width: 10, colOffset: 1, roomRows: 30},
+		{width: 2, colOffset: 0, roomRows: 10},
+		{width: 31, colOffset: 0, roomRows: 1},
+	}
+
+	for i, testCase := range testCases {
+		t.Run(fmt.Sprintf("#%d", i+1), func(t *testing.T) {
+			t.Parallel()
+
+			stream := generator.NewEllerStream(testCase.width, testCase.colOffset, uint64(i), domain.DefaultTerrain())
+			cells := make([][]domain.CellType, 0, 2*testCase.roomRows)
+
+			for range testCase.roomRows - 1 {
+				rooms, connectors := stream.Next()
+				cells = append(cells, rooms, connectors)
+			}
+
+			cells = append(cells, stream.Last())
+
+			cntPassages := 0
+
+			for _, row := range cells {
+				require.Len(t, row, testCase.width, "row must have the stream width")
+
+				for _, tpe := range row {
+					if tpe != domain.Wall {
+						cntPassages++
+					}
+				}
+			}
+
+			roomCols := (testCase.width - testCase.colOffset + 1) / 2
+			start := domain.NewCoord(0, testCase.colOffset)
+
+			// a perfect maze over the rooms has exactly rooms-1 connections
+			require.Equal(t, 2*roomCols*testCase.roomRows-1, cntPassages, "maze must be perfect")
+			require.Equal(t, cntPassages, countReachable(cells, start), "all rooms must be connected")
+		})
+	}
+}
+
+func TestParseStrategy(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		input    string
+		expected generator.Strategy
+	}{
+		{
+			input:    "newest",
+			expected: generator.Strategy{generator.NewWeightedSelection(generator.SelectNewest, 1)},
+		},
+		{
+			input: "75% newest, 25% random",
+			expected: generator.Strategy{
+				generator.NewWeightedSelection(generator.SelectNewest, 75),
+				generator.NewWeightedSelection(generator.SelectRandom, 25),
+			},
+		},
+		{
+			input: " 3 Oldest ,random",
+			expected: generator.Strategy{
+				generator.NewWeightedSelection(generator.SelectOldest, 3),
+				generator.NewWeightedSelection(generator.SelectRandom, 1),
+			},
+		},
+	}
+
+	for i, testCase := range testCases {
+		t.Run(fmt.Sprintf("#%d", i+1), func(t *testing.T) {
+			t.Parallel()
+
+			got, err := generator.ParseStrategy(testCase.input)
+
+			require.NoError(t, err, "strategy should be parsed without error")
+			require.Equal(t, testCase.expected, got, "strategies should be equal")
+		})
+	}
+}
+
+func TestStrategyString(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		strategy generator.Strategy
+		expected string
+	}{
+		{
+			strategy: generator.Strategy{generator.NewWeightedSelection(generator.SelectNewest, 1)},
+			expected: "100% newest",
+		},
+		{
+			strategy: generator.Strategy{
+				generator.NewWeightedSelection(generator.SelectNewest, 75),
+				generator.NewWeightedSelection(generator.SelectRandom, 25),
+			},
+			expected: "75% newest, 25% random",
+		},
+		{
+			strategy: generator.Strategy{
+				generator.NewWeightedSelection(generator.SelectOldest, 1),
+				generator.NewWeightedSelection(generator.SelectRandom, 3),
+			},
+			expected: "25% oldest, 75% random",
+		},
+		{
+			strategy: generator.Strategy{
+				generator.NewWeightedSelection(generator.SelectOldest, 1),
+				generator.NewWeightedSelection(generator.SelectNewest, 2),
+			},
+			expected: "1 oldest, 2 newest",
+		},
+	}
+
+	for i, testCase := range testCases {
+		t.Run(fmt.Sprintf("#%d", i+1), func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, testCase.expected, testCase.strategy.String(), "strategies should be printed equally")
+
+			parsed, err := generator.ParseStrategy(testCase.strategy.String())
+
+			require.NoError(t, err, "printed strategy should be parsed without error")
+			require.Equal(t, testCase.strategy.String(), parsed.String(), "printed strategy should be parsed back")
+		})
+	}
+}
+
+func TestParseStrategyWithError(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		input string
+	}{
+		{input: ""},
+		{input: "latest"},
+		{input: "0% newest"},
+		{input: "-5% random"},
+		{input: "50% newest,"},
+		{input: "50% newest random"},
+	}
+
+	for i, testCase := range testCases {
+		t.Run(fmt.Sprintf("#%d", i+1), func(t *testing.T) {
+			t.Parallel()
+
+			_, err := generator.ParseStrategy(testCase.input)
+
+			require.ErrorAs(t, err, &generator.ErrInvalidStrategy{}, "strategy should be invalid")
+		})
+	}
+}
+
+func TestGenerateMazeWithTerrain(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		data      domain.MazeData
+		algorithm generator.Algorithm
+		terrain   domain.TerrainProfile
+	}{
+		{
+			data:      domain.NewMazeData(10, 10, domain.NewCoord(0, 0), domain.NewCoord(9, 9)),
+			algorithm: generator.NewPrim(),
+			terrain:   domain.TerrainProfile{domain.Sand: 1},
+		},
+		{
+			data:      domain.NewMazeData(12, 8, domain.NewCoord(11, 0), domain.NewCoord(0, 7)),
+			algorithm: generator.NewKruskal(),
+			terrain:   domain.TerrainProfile{domain.Money: 3, domain.River: 1},
+		},
+		{
+			data:      domain.NewMazeData(9, 15, domain.NewCoord(0, 3), domain.NewCoord(8, 3)),
+			algorithm: generator.NewRecursiveDivision(2),
+			terrain:   domain.TerrainProfile{},
+		},
+	}
+
+	for i, testCase := range testCases {
+		t.Run(fmt.Sprintf("#%d", i+1), func(t *testing.T) {
+			t.Parallel()
+
+			maze := generateWithDiscard(
+				t,
+				generator.New(testCase.algorithm, generator.WithTerrain(testCase.terrain)),
+				testCase.data,
+			)
+
+			for _, row := range maze.Cells {
+				for _, tpe := range row {
+					if tpe == domain.Wall {
+						continue
+					}
+
+					if testCase.terrain.Total() == 0 {
+						require.Equal(t, domain.Passage, tpe, "empty profile should give plain passages")
+					} else {
+						require.Positive(t, testCase.terrain[tpe], "cell type should be in the terrain profile")
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateMazeWithBiomes(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		data      domain.MazeData
+		algorithm generator.Algorithm
+		terrain   domain.TerrainProfile
+	}{
+		{
+			data:      domain.NewMazeData(20, 20, domain.NewCoord(0, 0), domain.NewCoord(19, 19)),
+			algorithm: generator.NewPrim(),
+			terrain:   domain.DefaultTerrain(),
+		},
+		{
+			data:      domain.NewMazeData(25, 15, domain.NewCoord(0, 7), domain.NewCoord(24, 7)),
+			algorithm: generator.NewRecursiveDivision(3),
+			terrain:   domain.TerrainProfile{domain.Passage: 2, domain.Sand: 1, domain.River: 1},
+		},
+		{
+			data:      domain.NewMazeData(16, 16, domain.NewCoord(15, 0), domain.NewCoord(0, 15)),
+			algorithm: generator.NewBacktrack(),
+			terrain:   domain.TerrainProfile{domain.Money: 1, domain.Sand: 1},
+		},
+	}
+
+	for i, testCase := range testCases {
+		t.Run(fmt.Sprintf("#%d", i+1), func(t *testing.T) {
+			t.Parallel()
+
+			gen := generator.New(
+				testCase.algorithm,
+				generator.WithTerrain(testCase.terrain),
+				generator.WithBiomes(true),
+			)
+			ch := make(chan domain.CellPaintingData)
+
+			var maze domain.Maze
+
+			var err error
+
+			go func() {
+				defer close(ch)
+				maze, err = gen.GenerateMaze(context.Background(), testCase.data, ch)
+			}()
+
+			drawMaze := make([][]domain.CellType, testCase.data.Height)
+			for i := range testCase.data.Height {
+				drawMaze[i] = make([]domain.CellType, testCase.data.Width)
+			}
+
+			for cellData := range ch {
+				drawMaze[cellData.Row][cellData.Col] = cellData.Tpe
+			}
+
+			require.NoError(t, err, "generate maze should return nil error")
+			compareMazes(t, maze, drawMaze)
+
+			counts := make(map[domain.CellType]int)
+			cntPassages := 0
+
+			for _, row := range maze.Cells {
+				for _, tpe := range row {
+					if tpe != domain.Wall {
+						counts[tpe]++
+						cntPassages++
+					}
+				}
+			}
+
+			for _, tpe := range []domain.CellType{domain.River, domain.Sand, domain.Money} {
+				if testCase.terrain[domain.Passage] == 0 && tpe == domain.Money {
+					// the most common type also takes cells left after rounding
+					continue
+				}
+
+				require.Equal(
+					t,
+					cntPassages*testCase.terrain[tpe]/testCase.terrain.Total(),
+					counts[tpe],
+					"biomes should keep shares of the terrain profile",
+				)
+			}
+		})
+	}
+}
+
+func countDeadEnds(maze domain.Maze) int {
+	dir := domain.DefaultDirection()
+	cnt := 0
+
+	for i, row := range maze.Cells {
+		for j, tpe := range row {
+			coord := domain.NewCoord(i, j)
+			if tpe == domain.Wall || coord == maze.Data.Start || coord == maze.Data.End {
+				continue
+			}
+
+			cntPassages := 0
+
+			for k := range dir.Rows {
+				newRow, newCol := i+dir.Rows[k], j+dir.Cols[k]
+				if min(newRow, newCol) >= 0 && newRow < len(maze.Cells) && newCol < len(row) &&
+					maze.Cells[newRow][newCol] != domain.Wall {
+					cntPassages++
+				}
+			}
+
+			if cntPassages == 1 {
+				cnt++
+			}
+		}
+	}
+
+	return cnt
+}
+
+func TestGenerateMazeWithBraid(t *testing.T) {
+	t.Parallel()
+
+	// braid 0 keeps the spanning tree, so the maze stays perfect
+	requirePerfect(t, generateWithDiscard(
+		t,
+		generator.New(generator.NewWilson(), generator.WithSeed(3), generator.WithBraid(0)),
+		domain.NewMazeData(31, 31, domain.NewCoord(0, 0), domain.NewCoord(30, 30)),
+	))
+
+	testCases := []struct {
+		data      domain.MazeData
+		algorithm func() generator.Algorithm
+		seed      uint64
+	}{
+		{
+			data:      domain.NewMazeData(20, 20, domain.NewCoord(0, 0), domain.NewCoord(19, 19)),
+			algorithm: func() generator.Algorithm { return generator.NewPrim() },
+			seed:      0,
+		},
+		{
+			data:      domain.NewMazeData(21, 15, domain.NewCoord(0, 0), domain.NewCoord(20, 14)),
+			algorithm: func() generator.Algorithm { return generator.NewHuntAndKill() },
+			seed:      3,
+		},
+		{
+			data:      domain.NewMazeData(17, 23, domain.NewCoord(16, 4), domain.NewCoord(0, 22)),
+			algorithm: func() generator.Algorithm { return generator.NewBacktrack() },
+			seed:      2,
+		},
+		{
+			data:      domain.NewMazeData(19, 19, domain.NewCoord(0, 9), domain.NewCoord(18, 9)),
+			algorithm: func() generator.Algorithm { return generator.NewWilson() },
+			seed:      3,
+		},
+	}
+
+	for i, testCase := range testCases {
+		t.Run(fmt.Sprintf("#%d", i+1), func(t *testing.T) {
+			t.Parallel()
+
+			perfect := generateWithDiscard(
+				t,
+				generator.New(testCase.algorithm(), generator.WithSeed(testCase.seed), generator.WithBraid(0)),
+				testCase.data,
+			)
+			braided := generateWithDiscard(
+				t,
+				generator.New(testCase.algorithm(), generator.WithSeed(testCase.seed), generator.WithBraid(1)),
+				testCase.data,
+			)
+
+			require.Positive(t, countDeadEnds(perfect), "maze without braiding should have dead ends")
+			require.Zero(t, countDeadEnds(braided), "fully braided maze shouldn't have dead ends")
+		})
+	}
+}
+
+func TestGenerateMazeWithWorkers(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		data      domain.MazeData
+		algorithm func() generator.Algorithm
+		opts      []generator.Option
+		workers   int
+	}{
+		{
+			data:      domain.NewMazeData(20, 20, domain.NewCoord(0, 0), domain.NewCoord(19, 19)),
+			algorithm: func() generator.Algorithm { return generator.NewPrim() },
+			opts:      []generator.Option{generator.WithWorkers(4)},
+			workers:   4,
+		},
+		{
+			data:      domain.NewMazeData(25, 15, domain.NewCoord(0, 7), domain.NewCoord(24, 7)),
+			algorithm: func() generator.Algorithm { return generator.NewBacktrack() },
+			opts: []generator.Option{
+				generator.WithOrigins(domain.NewCoord(12, 7), domain.NewCoord(6, 2)),
+			},
+			workers: 4,
+		},
+		{
+			data:      domain.NewMazeData(21, 21, domain.NewCoord(0, 0), domain.NewCoord(20, 20)),
+			algorithm: func() generator.Algorithm { return generator.NewBacktrack() },
+			opts: []generator.Option{
+				generator.WithOrigins(domain.NewCoord(10, 10)),
+				generator.WithWorkers(6),
+				generator.WithConcurrency(2),
+			},
+			workers: 6,
+		},
+		{
+			data:      domain.NewMazeData(10, 10, domain.NewCoord(0, 0), domain.NewCoord(9, 9)),
+			algorithm: func() generator.Algorithm { return generator.NewPrim() },
+			opts:      []generator.Option{generator.WithWorkers(1)},
+			workers:   2,
+		},
+	}
+
+	for i, testCase := range testCases {
+		t.Run(fmt.Sprintf("#%d", i+1), func(t *testing.T) {
+			t.Parallel()
+
+			gen := generator.New(testCase.algorithm(), append(testCase.opts, generator.WithSeed(uint64(i)))...)
+			ch := make(chan domain.CellPaintingData)
+
+			var maze domain.Maze
+
+			var err error
+
+			go func() {
+				defer close(ch)
+				maze, err = gen.GenerateMaze(context.Background(), testCase.data, ch)
+			}()
+
+			drawMaze := make([][]domain.CellType, testCase.data.Height)
+			for i := range testCase.data.Height {
+				drawMaze[i] = make([]domain.CellType, testCase.data.Width)
+			}
+
+			senders := make(map[int]struct{})
+
+			for cellData := range ch {
+				drawMaze[cellData.Row][cellData.Col] = cellData.Tpe
+				senders[cellData.SenderID] = struct{}{}
+			}
+
+			require.NoError(t, err, "generate maze should return nil error")
+			compareMazes(t, maze, drawMaze)
+
+			for id := range testCase.workers + 1 {
+				require.Contains(t, senders, id, "every worker and the merge should paint cells")
+			}
+
+			require.Len(t, senders, testCase.workers+1, "there should be a sender per worker and the merge")
+
+			sequential := generateWithDiscard(
+				t,
+				generator.New(
+					testCase.algorithm(),
+					append(testCase.opts, generator.WithSeed(uint64(i)), generator.WithConcurrency(1))...,
+				),
+				testCase.data,
+			)
+
+			require.Equal(t, maze, sequential, "concurrency limit shouldn't change the maze")
+		})
+	}
+}
+
+func TestGenerateMazeWithInvalidOrigin(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		data   domain.MazeData
+		origin domain.Coord
+	}{
+		{
+			data:   domain.NewMazeData(10, 10, domain.NewCoord(0, 0), domain.NewCoord(9, 9)),
+			origin: domain.NewCoord(10, 5),
+		},
+		{
+			data:   domain.NewMazeData(5, 8, domain.NewCoord(0, 0), domain.NewCoord(4, 7)),
+			origin: domain.NewCoord(2, -1),
+		},
+	}
+
+	for i, testCase := range testCases {
+		t.Run(fmt.Sprintf("#%d", i+1), func(t *testing.T) {
+			t.Parallel()
+
+			gen := generator.New(generator.NewPrim(), generator.WithOrigins(testCase.origin))
+			ch := make(chan domain.CellPaintingData)
+
+			go func() {
+				for range ch {
+				}
+			}()
+
+			_, err := gen.GenerateMaze(context.Background(), testCase.data, ch)
+			close(ch)
+
+			require.ErrorAs(t, err, &generator.ErrInvalidOrigin{}, "origin should be invalid")
+		})
+	}
+}
+
+func TestGenerateMazeWithMerge(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		data      domain.MazeData
+		algorithm func() generator.Algorithm
+		workers   int
+	}{
+		{
+			data:      domain.NewMazeData(20, 20, domain.NewCoord(0, 0), domain.NewCoord(19, 19)),
+			algorithm: func() generator.Algorithm { return generator.NewPrim() },
+			workers:   2,
+		},
+		{
+			data:      domain.NewMazeData(25, 15, domain.NewCoord(0, 7), domain.NewCoord(24, 7)),
+			algorithm: func() generator.Algorithm { return generator.NewBacktrack() },
+			workers:   4,
+		},
+		{
+			data:      domain.NewMazeData(21, 21, domain.NewCoord(0, 0), domain.NewCoord(20, 20)),
+			algorithm: func() generator.Algorithm { return generator.NewKruskal() },
+			workers:   3,
+		},
+	}
+
+	strategies := []generator.MergeStrategy{
+		generator.MergeRandom,
+		generator.MergeCheapest,
+		generator.MergeStartSide,
+		generator.MergeSeam,
+		generator.MergeInterleave,
+	}
+
+	for i, testCase := range testCases {
+		t.Run(fmt.Sprintf("#%d", i+1), func(t *testing.T) {
+			t.Parallel()
+
+			mazes := make(map[generator.MergeStrategy]domain.Maze)
+
+			for _, strategy := range strategies {
+				mazes[strategy] = generateWithDiscard(
+					t,
+					generator.New(
+						testCase.algorithm(),
+						generator.WithSeed(uint64(i)),
+						generator.WithWorkers(testCase.workers),
+						generator.WithMerge(strategy),
+					),
+					testCase.data,
+				)
+			}
+
+			union := mazes[generator.MergeRandom]
+
+			for _, strategy := range strategies {
+				maze := mazes[strategy]
+
+				require.NotEqual(t, domain.Wall, maze.Cells[maze.Data.Start.Row][maze.Data.Start.Col],
+					"start should be carved with %s merge", strategy)
+				require.NotEqual(t, domain.Wall, maze.Cells[maze.Data.End.Row][maze.Data.End.Col],
+					"end should be carved with %s merge", strategy)
+
+				for row := range union.Cells {
+					for col, tpe := range union.Cells[row] {
+						got := maze.Cells[row][col]
+
+						if strategy == generator.MergeSeam {
+							if tpe == domain.Wall && !slices.Contains(maze.Repaired, domain.NewCoord(row, col)) {
+								require.Equal(t, domain.Wall, got, "seam merge should carve only united cells")
+							}
+
+							continue
+						}
+
+						require.Equal(t, tpe == domain.Wall, got == domain.Wall,
+							"%s merge should carve the union of partial mazes", strategy)
+						require.LessOrEqual(t, mazes[generator.MergeCheapest].Cells[row][col].Cost(), got.Cost(),
+							"cheapest merge should have the lowest cost in every cell")
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateMazeWithRepair(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		data        domain.MazeData
+		algorithm   func() generator.Algorithm
+		workers     int
+		seed        uint64
+		needsRepair bool
+	}{
+		{
+			data:        domain.NewMazeData(21, 21, domain.NewCoord(0, 0), domain.NewCoord(19, 19)),
+			algorithm:   func() generator.Algorithm { return generator.NewKruskal() },
+			workers:     3,
+			seed:        15,
+			needsRepair: true,
+		},
+		{
+			data:      domain.NewMazeData(30, 30, domain.NewCoord(0, 15), domain.NewCoord(29, 15)),
+			algorithm: func() generator.Algorithm { return generator.NewPrim() },
+			workers:   8,
+			seed:      1,
+		},
+		{
+			data:      domain.NewMazeData(25, 25, domain.NewCoord(24, 0), domain.NewCoord(0, 24)),
+			algorithm: func() generator.Algorithm { return generator.NewRecursiveDivision(2) },
+			workers:   5,
+			seed:      2,
+		},
+		{
+			data:        domain.NewMazeData(19, 31, domain.NewCoord(0, 0), domain.NewCoord(17, 29)),
+			algorithm:   func() generator.Algorithm { return generator.NewWilson() },
+			workers:     6,
+			seed:        159,
+			needsRepair: true,
+		},
+	}
+
+	for i, testCase := range testCases {
+		t.Run(fmt.Sprintf("#%d", i+1), func(t *testing.T) {
+			t.Parallel()
+
+			gen := generator.New(
+				testCase.algorithm(),
+				generator.WithSeed(testCase.seed),
+				generator.WithWorkers(testCase.workers),
+				generator.WithMerge(generator.MergeSeam),
+			)
+			ch := make(chan domain.CellPaintingData)
+
+			var maze domain.Maze
+
+			var err error
+
+			go func() {
+				defer close(ch)
+				maze, err = gen.GenerateMaze(context.Background(), testCase.data, ch)
+			}()
+
+			drawMaze := make([][]domain.CellType, testCase.data.Height)
+			for i := range testCase.data.Height {
+				drawMaze[i] = make([]domain.CellType, testCase.data.Width)
+			}
+
+			for cellData := range ch {
+				drawMaze[cellData.Row][cellData.Col] = cellData.Tpe
+			}
+
+			require.NoError(t, err, "generate maze should return nil error")
+			compareMazes(t, maze, drawMaze)
+
+			require.Equal(t, testCase.needsRepair, len(maze.Repaired) != 0, "walls should be opened only when needed")
+
+			for _, c := range maze.Repaired {
+				require.NotEqual(t, domain.Wall, maze.Cells[c.Row][c.Col], "repaired cell should be carved")
+			}
+
+			require.True(t, pathExists(maze.Cells, maze.Data.Start, maze.Data.End), "end should be reachable from start")
+		})
+	}
+}
+
+func pathExists(cells [][]domain.CellType, start, end domain.Coord) bool {
+	dir := domain.DefaultDirection()
+	visited := map[domain.Coord]struct{}{start: {}}
+	queue := []domain.Coord{start}
+
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+
+		if cur == end {
+			return true
+		}
+
+		for i := range dir.Rows {
+			next := domain.NewCoord(cur.Row+dir.Rows[i], cur.Col+dir.Cols[i])
+			if min(next.Row, next.Col) < 0 || next.Row >= len(cells) || next.Col >= len(cells[next.Row]) ||
+				cells[next.Row][next.Col] == domain.Wall {
+				continue
+			}
+
+			if _, ok := visited[next]; !ok {
+				visited[next] = struct{}{}
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	return false
+}
+
+func TestGenerateMazeOnHexGrid(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		algorithm generator.Algorithm
+		height    int
+		width     int
+	}{
+		{algorithm: generator.NewPrim(), height: 15, width: 20},
+		{algorithm: generator.NewBacktrack(), height: 12, width: 12},
+		{algorithm: generator.NewKruskal(), height: 17, width: 13},
+		{algorithm: generator.NewWilson(), height: 11, width: 18},
+		{algorithm: generator.NewEller(), height: 15, width: 15},
+		{algorithm: generator.NewRecursiveDivision(2), height: 13, width: 21},
+		{algorithm: generator.NewHuntAndKill(), height: 17, width: 9},
+		{algorithm: generator.NewAldousBroder(), height: 9, width: 9},
+		{algorithm: generator.NewGrowingTree(nil), height: 21, width: 17},
+	}
+
+	for i, testCase := range testCases {
+		t.Run(fmt.Sprintf("#%d", i+1), func(t *testing.T) {
+			t.Parallel()
+
+			data := domain.NewMazeData(
+				testCase.height,
+				testCase.width,
+				domain.NewCoord(0, 0),
+				domain.NewCoord(testCase.height-1, testCase.width-1),
+			)
+			data.Topology = domain.Hex
+
+			maze := generateWithDiscard(t, generator.New(testCase.algorithm, generator.WithSeed(uint64(i))), data)
+
+			require.Empty(t, maze.Repaired, "hex maze should be connected without repair")
+			require.Equal(t, domain.Hex, maze.Data.Topology, "maze should keep the topology")
+			require.True(t, topologyPathExists(maze), "end should be reachable from start over hex neighbours")
+		})
+	}
+}
+
+func hasRoom(cells [][]domain.CellType, size int) bool {
+	for i := 0; i+size <= len(cells); i++ {
+		for j := 0; j+size <= len(cells[i]); j++ {
+			if isRoom(cells, i, j, size) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func isRoom(cells [][]domain.CellType, top, left, size int) bool {
+	return isOpenRect(cells, top, left, size, size)
+}
+
+// hasOpenRect reports whether the cells hold a carved rectangle of the given
+// height and width.
+func hasOpenRect(cells [][]domain.CellType, height, width int) bool {
+	for i := 0; i+height <= len(cells); i++ {
+		for j := 0; j+width <= len(cells[i]); j++ {
+			if isOpenRect(cells, i, j, height, width) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func isOpenRect(cells [][]domain.CellType, top, left, height, width int) bool {
+	for i := top; i < top+height; i++ {
+		for j := left; j < left+width; j++ {
+			if cells[i][j] == domain.Wall {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// hasDividingWall reports whether a row or a column of walls with a single
+// gap crosses the whole maze.
+func hasDividingWall(cells [][]domain.CellType) bool {
+	for _, row := range cells {
+		if countOpen(row) == 1 {
+			return true
+		}
+	}
+
+	for j := range cells[0] {
+		col := make([]domain.CellType, len(cells))
+		for i, row := range cells {
+			col[i] = row[j]
+		}
+
+		if countOpen(col) == 1 {
+			return true
+		}
+	}
+
+	return false
+}
+
+func countOpen(line []domain.CellType) int {
+	cnt := 0
+
+	for _, tpe := range line {
+		if tpe != domain.Wall {
+			cnt++
+		}
+	}
+
+	return cnt
+}
+
+func TestGenerateRecursiveDivision(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		data           domain.MazeData
+		minChamberSize int
+	}{
+		{
+			data:           domain.NewMazeData(21, 31, domain.NewCoord(0, 0), domain.NewCoord(20, 30)),
+			minChamberSize: generator.DefaultMinChamberSize,
+		},
+		{
+			data:           domain.NewMazeData(15, 15, domain.NewCoord(0, 7), domain.NewCoord(14, 3)),
+			minChamberSize: 4,
+		},
+		{
+			data:           domain.NewMazeData(31, 11, domain.NewCoord(30, 10), domain.NewCoord(0, 0)),
+			minChamberSize: 2,
+		},
+	}
+
+	for i, testCase := range testCases {
+		t.Run(fmt.Sprintf("#%d", i+1), func(t *testing.T) {
+			t.Parallel()
+
+			maze := generateWithDiscard(
+				t,
+				generator.New(generator.NewRecursiveDivision(testCase.minChamberSize), generator.WithSeed(uint64(i))),
+				testCase.data,
+			)
+
+			// the first wall splits the whole field, later ones stop at it
+			require.True(t, hasDividingWall(maze.Cells), "a wall with a single gap should cross the maze")
+			require.True(t, pathExists(maze.Cells, maze.Data.Start, maze.Data.End), "end should be reachable from start")
+		})
+	}
+}
+
+func TestGenerateDungeon(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		topology    domain.Topology
+		height      int
+		width       int
+		minRoomSize int
+		maxRoomSize int
+	}{
+		{topology: domain.Square, height: 30, width: 30, minRoomSize: 3, maxRoomSize: 7},
+		{topology: domain.Square, height: 15, width: 40, minRoomSize: 2, maxRoomSize: 4},
+		{topology: domain.Hex, height: 25, width: 20, minRoomSize: 3, maxRoomSize: 5},
+		{topology: domain.Hex, height: 12, width: 12, minRoomSize: 4, maxRoomSize: 4},
+		{topology: domain.Square, height: 20, width: 40, minRoomSize: 3, maxRoomSize: 7},
+		{topology: domain.Square, height: 40, width: 40, minRoomSize: 3, maxRoomSize: 5},
+	}
+
+	for i, testCase := range testCases {
+		t.Run(fmt.Sprintf("#%d", i+1), func(t *testing.T) {
+			t.Parallel()
+
+			data := domain.NewMazeData(
+				testCase.height,
+				testCase.width,
+				domain.NewCoord(0, 0),
+				domain.NewCoord(testCase.height-1, testCase.width-1),
+			)
+			data.Topology = testCase.topology
+
+			maze := generateWithDiscard(
+				t,
+				generator.New(
+					generator.NewDungeon(testCase.minRoomSize, testCase.maxRoomSize),
+					generator.WithSeed(uint64(i)),
+				),
+				data,
+			)
+
+			require.True(t, hasRoom(maze.Cells, testCase.minRoomSize), "dungeon should have rooms")
+			require.True(t, topologyPathExists(maze), "end should be reachable from start")
+
+			// overlapping rooms would fuse into areas longer than any room,
+			// while a corridor along a room widens it by a single cell
+			side, longest := max(testCase.minRoomSize, 3), testCase.maxRoomSize+2
+			require.False(t, hasOpenRect(maze.Cells, side, longest), "rooms shouldn't overlap")
+			require.False(t, hasOpenRect(maze.Cells, longest, side), "rooms shouldn't overlap")
+
+			// the dungeon is built once, so other workers don't change it
+			workers := generateWithDiscard(
+				t,
+				generator.New(
+					generator.NewDungeon(testCase.minRoomSize, testCase.maxRoomSize),
+					generator.WithSeed(uint64(i)),
+					generator.WithWorkers(4),
+				),
+				data,
+			)
+			require.Equal(t, maze.Cells, workers.Cells, "dungeon shouldn't depend on the number of workers")
+		})
+	}
+}
+
+// defaultCave returns the cave generator with the default parameters.
+func defaultCave() *generator.Cave {
+	return generator.NewCave(generator.DefaultCaveFill, generator.DefaultCaveIterations, generator.DefaultCaveRule())
+}
+
+func TestGenerateCave(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		topology domain.Topology
+		height   int
+		width    int
+		cave     *generator.Cave
+		workers  int
+		merge    generator.MergeStrategy
+		seed     uint64
+	}{
+		{
+			topology: domain.Square,
+			height:   30,
+			width:    40,
+			cave:     defaultCave(),
+			seed:     0,
+		},
+		{
+			topology: domain.Square,
+			height:   20,
+			width:    20,
+			cave:     generator.NewCave(0.55, 5, generator.NewCaveRule(5, 4)),
+			seed:     1,
+		},
+		{
+			topology: domain.Torus,
+			height:   24,
+			width:    18,
+			cave:     generator.NewCave(0.4, 3, generator.NewCaveRule(6, 5)),
+			seed:     2,
+		},
+		{
+			topology: domain.Square,
+			height:   12,
+			width:    12,
+			cave:     generator.NewCave(1, 0, generator.DefaultCaveRule()),
+			seed:     3,
+		},
+		{
+			topology: domain.Square,
+			height:   30,
+			width:    30,
+			cave:     defaultCave(),
+			workers:  4,
+			merge:    generator.MergeSeam,
+			seed:     28,
+		},
+		{
+			topology: domain.Torus,
+			height:   20,
+			width:    30,
+			cave:     generator.NewCave(0.5, 4, generator.DefaultCaveRule()),
+			workers:  3,
+			merge:    generator.MergeSeam,
+			seed:     1,
+		},
+	}
+
+	for i, testCase := range testCases {
+		t.Run(fmt.Sprintf("#%d", i+1), func(t *testing.T) {
+			t.Parallel()
+
+			data := domain.NewMazeData(
+				testCase.height,
+				testCase.width,
+				domain.NewCoord(0, 0),
+				domain.NewCoord(testCase.height-1, testCase.width-1),
+			)
+			data.Topology = testCase.topology
+
+			maze := generateWithDiscard(
+				t,
+				generator.New(
+					testCase.cave,
+					generator.WithSeed(testCase.seed),
+					generator.WithWorkers(testCase.workers),
+					generator.WithMerge(testCase.merge),
+				),
+				data,
+			)
+
+			open := 0
+
+			for _, row := range maze.Cells {
+				for _, tpe := range row {
+					if tpe != domain.Wall {
+						open++
+					}
+				}
+			}
+
+			require.Equal(t, open, countTopologyReachable(maze), "every open cell should be reachable from start")
+			require.True(t, topologyPathExists(maze), "end should be reachable from start")
+		})
+	}
+}
+
+func TestGenerateCaveOnHexGrid(t *testing.T) {
+	t.Parallel()
+
+	data := domain.NewMazeData(10, 10, domain.NewCoord(0, 0), domain.NewCoord(9, 9))
+	data.Topology = domain.Hex
+
+	gen := generator.New(defaultCave())
+
+	_, err := gen.GenerateMaze(context.Background(), data, nil)
+	require.ErrorAs(t, err, &generator.ErrUnsupportedTopology{}, "caves should reject hex grids")
+}
+
+func countTopologyReachable(maze domain.Maze) int {
+	visited := map[domain.Coord]struct{}{maze.Data.Start: {}}
+	queue := []domain.Coord{maze.Data.Start}
+
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+
+		for _, next := range maze.Data.Neighbours(cur) {
+			if !maze.Passable(cur, next) {
+				continue
+			}
+
+			if _, ok := visited[next]; !ok {
+				visited[next] = struct{}{}
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	return len(visited)
+}
+
+func topologyPathExists(maze domain.Maze) bool {
+	visited := map[domain.Coord]struct{}{maze.Data.Start: {}}
+	queue := []domain.Coord{maze.Data.Start}
+
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+
+		if cur == maze.Data.End {
+			return true
+		}
+
+		for _, next := range maze.Data.Neighbours(cur) {
+			if !maze.Passable(cur, next) {
+				continue
+			}
+
+			if _, ok := visited[next]; !ok {
+				visited[next] = struct{}{}
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	return false
+}
+
+func TestGenerateMazeOnPolarGrid(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		algorithm generator.Algorithm
+		rings     int
+		workers   int
+	}{
+		{algorithm: generator.NewPrim(), rings: 8, workers: 2},
+		{algorithm: generator.NewBacktrack(), rings: 10, workers: 2},
+		{algorithm: generator.NewPrim(), rings: 12, workers: 4},
+		{algorithm: generator.NewBacktrack(), rings: 2, workers: 3},
+	}
+
+	for i, testCase := range testCases {
+		t.Run(fmt.Sprintf("#%d", i+1), func(t *testing.T) {
+			t.Parallel()
+
+			data := domain.NewPolarMazeData(testCase.rings)
+			maze := generateWithDiscard(
+				t,
+				generator.New(
+					testCase.algorithm,
+					generator.WithSeed(uint64(i)),
+					generator.WithWorkers(testCase.workers),
+				),
+				data,
+			)
+
+			for row := range maze.Cells {
+				for col := domain.RingCells(row); col < data.Width; col++ {
+					require.Equal(t, domain.Wall, maze.Cells[row][col], "cells beyond the ring should stay walls")
+				}
+			}
+
+			require.True(t, topologyPathExists(maze), "rim should be reachable from the centre")
+		})
+	}
+}
+
+func TestGenerateMazeWithUnsupportedTopology(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		algorithm generator.Algorithm
+	}{
+		{algorithm: generator.NewKruskal()},
+		{algorithm: generator.NewWilson()},
+		{algorithm: generator.NewEller()},
+		{algorithm: generator.NewRecursiveDivision(1)},
+		{algorithm: generator.NewHuntAndKill()},
+		{algorithm: generator.NewAldousBroder()},
+		{algorithm: generator.NewGrowingTree(nil)},
+		{algorithm: generator.NewDungeon(generator.DefaultMinRoomSize, generator.DefaultMaxRoomSize)},
+		{algorithm: defaultCave()},
+	}
+
+	for i, testCase := range testCases {
+		t.Run(fmt.Sprintf("#%d", i+1), func(t *testing.T) {
+			t.Parallel()
+
+			gen := generator.New(testCase.algorithm)
+			ch := make(chan domain.CellPaintingData)
+
+			go func() {
+				for range ch {
+				}
+			}()
+
+			_, err := gen.GenerateMaze(context.Background(), domain.NewPolarMazeData(6), ch)
+			close(ch)
+
+			require.ErrorAs(t, err, &generator.ErrUnsupportedTopology{}, "algorithm should reject polar grids")
+		})
+	}
+}
+
+func TestGenerateMazeOnLevels(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		algorithm generator.Algorithm
+		levels    int
+		workers   int
+		braid     float64
+		topology  domain.Topology
+	}{
+		{algorithm: generator.NewPrim(), levels: 2, workers: 2, topology: domain.Square},
+		{algorithm: generator.NewBacktrack(), levels: 3, workers: 2, topology: domain.Square},
+		{algorithm: generator.NewPrim(), levels: 4, workers: 5, braid: 0.5, topology: domain.Square},
+		{algorithm: generator.NewBacktrack(), levels: 3, workers: 3, braid: 1, topology: domain.Hex},
+		{algorithm: generator.NewPrim(), levels: 1, workers: 2, topology: domain.Square},
+	}
+
+	for i, testCase := range testCases {
+		t.Run(fmt.Sprintf("#%d", i+1), func(t *testing.T) {
+			t.Parallel()
+
+			data := domain.NewLevelMazeData(testCase.levels, 12, 15, domain.NewCoord(0, 0), domain.NewCoord(11, 14))
+			data.Topology = testCase.topology
+
+			maze := generateWithDiscard(
+				t,
+				generator.New(
+					testCase.algorithm,
+					generator.WithSeed(uint64(i)),
+					generator.WithWorkers(testCase.workers),
+					generator.WithBraid(testCase.braid),
+				),
+				data,
+			)
+
+			require.Len(t, maze.Cells, testCase.levels*data.Height, "floors should be stacked in cells")
+			require.Equal(t, testCase.levels-1, maze.Data.End.Level, "maze should end on the top floor")
+			require.True(t, topologyPathExists(maze), "top floor should be reachable from the ground floor")
+
+			stairs := 0
+
+			for row := range maze.Cells {
+				for col, tpe := range maze.Cells[row] {
+					if tpe != domain.Stairs {
+						continue
+					}
+
+					stairs++
+					coord := data.CoordAt(row, col)
+
+					linked := slices.ContainsFunc(data.Neighbours(coord), func(next domain.Coord) bool {
+						return next.Level != coord.Level && maze.Cell(next) == domain.Stairs
+					})
+					require.True(t, linked, "stairs should lead to another floor")
+				}
+			}
+
+			require.Equal(t, testCase.levels > 1, stairs > 0, "only multi-level mazes should have stairs")
+		})
+	}
+}
+
+func TestGenerateMazeWithUnsupportedLevels(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		algorithm generator.Algorithm
+	}{
+		{algorithm: generator.NewKruskal()},
+		{algorithm: generator.NewWilson()},
+		{algorithm: generator.NewEller()},
+		{algorithm: generator.NewRecursiveDivision(1)},
+		{algorithm: generator.NewHuntAndKill()},
+		{algorithm: generator.NewAldousBroder()},
+		{algorithm: generator.NewGrowingTree(nil)},
+		{algorithm: generator.NewDungeon(generator.DefaultMinRoomSize, generator.DefaultMaxRoomSize)},
+		{algorithm: defaultCave()},
+	}
+
+	for i, testCase := range testCases {
+		t.Run(fmt.Sprintf("#%d", i+1), func(t *testing.T) {
+			t.Parallel()
+
+			gen := generator.New(testCase.algorithm)
+			ch := make(chan domain.CellPaintingData)
+
+			go func() {
+				for range ch {
+				}
+			}()
+
+			data := domain.NewLevelMazeData(2, 9, 9, domain.NewCoord(0, 0), domain.NewCoord(8, 8))
+			_, err := gen.GenerateMaze(context.Background(), data, ch)
+			close(ch)
+
+			require.ErrorAs(t, err, &generator.ErrUnsupportedLevels{}, "algorithm should reject multi-level mazes")
+		})
+	}
+}
+
+func TestGenerateMazeOnTorus(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		algorithm generator.Algorithm
+		height    int
+		width     int
+	}{
+		{algorithm: generator.NewPrim(), height: 15, width: 21},
+		{algorithm: generator.NewBacktrack(), height: 12, width: 9},
+		{algorithm: generator.NewKruskal(), height: 16, width: 12},
+		{algorithm: generator.NewWilson(), height: 10, width: 18},
+		{algorithm: generator.NewHuntAndKill(), height: 14, width: 14},
+		{algorithm: generator.NewAldousBroder(), height: 8, width: 10},
+		{algorithm: generator.NewGrowingTree(nil), height: 20, width: 16},
+		{algorithm: generator.NewKruskal(), height: 4, width: 2},
+		{algorithm: defaultCave(), height: 15, width: 15},
+	}
+
+	for i, testCase := range testCases {
+		t.Run(fmt.Sprintf("#%d", i+1), func(t *testing.T) {
+			t.Parallel()
+
+			data := domain.NewMazeData(
+				testCase.height,
+				testCase.width,
+				domain.NewCoord(0, 0),
+				domain.NewCoord(testCase.height-1, testCase.width-1),
+			)
+			data.Topology = domain.Torus
+
+			maze := generateWithDiscard(
+				t,
+				generator.New(testCase.algorithm, generator.WithSeed(uint64(i)), generator.WithMerge(generator.MergeSeam)),
+				data,
+			)
+
+			require.True(t, topologyPathExists(maze), "end should be reachable from start on the torus")
+
+			wraps := 0
+
+			for row := range data.Height {
+				if maze.Cells[row][0] != domain.Wall && maze.Cells[row][data.Width-1] != domain.Wall {
+					wraps++
+				}
+			}
+
+			for col := range data.Width {
+				if maze.Cells[0][col] != domain.Wall && maze.Cells[data.Height-1][col] != domain.Wall {
+					wraps++
+				}
+			}
+
+			require.Positive(t, wraps, "passages should go across the edges")
+		})
+	}
+}
+
+func TestGenerateMazeWithUnsupportedTorus(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		algorithm generator.Algorithm
+		height    int
+		width     int
+		err       error
+	}{
+		{algorithm: generator.NewEller(), height: 10, width: 10, err: &generator.ErrUnsupportedTopology{}},
+		{algorithm: generator.NewRecursiveDivision(1), height: 9, width: 10, err: &generator.ErrUnsupportedTopology{}},
+		{algorithm: generator.NewKruskal(), height: 9, width: 10, err: &generator.ErrOddTorus{}},
+		{algorithm: generator.NewWilson(), height: 10, width: 11, err: &generator.ErrOddTorus{}},
+		{algorithm: generator.NewGrowingTree(nil), height: 7, width: 7, err: &generator.ErrOddTorus{}},
+		{algorithm: generator.NewDungeon(1, 3), height: 10, width: 10, err: &generator.ErrUnsupportedTopology{}},
+	}
+
+	for i, testCase := range testCases {
+		t.Run(fmt.Sprintf("#%d", i+1), func(t *testing.T) {
+			t.Parallel()
+
+			gen := generator.New(testCase.algorithm)
+			ch := make(chan domain.CellPaintingData)
+
+			go func() {
+				for range ch {
+				}
+			}()
+
+			data := domain.NewMazeData(
+				testCase.height,
+				testCase.width,
+				domain.NewCoord(0, 0),
+				domain.NewCoord(testCase.height-1, testCase.width-1),
+			)
+			data.Topology = domain.Torus
+
+			_, err := gen.GenerateMaze(context.Background(), data, ch)
+			close(ch)
+
+			require.ErrorAs(t, err, testCase.err, "algorithm should reject the torus")
+		})
+	}
+}
+
+// ringMask returns a square mask with a square hole in the middle.
+func ringMask(size, hole int) domain.Mask {
+	msk := make(domain.Mask, size)
+	from := (size - hole) / 2
+
+	for i := range size {
+		msk[i] = make([]bool, size)
+
+		for j := range size {
+			msk[i][j] = i < from || i >= from+hole || j < from || j >= from+hole
+		}
+	}
+
+	return msk
+}
+
+func TestGenerateMazeWithMask(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		algorithm generator.Algorithm
+		workers   int
+		braid     float64
+		levels    int
+	}{
+		{algorithm: generator.NewPrim(), workers: 2, levels: 1},
+		{algorithm: generator.NewBacktrack(), workers: 2, levels: 1},
+		{algorithm: generator.NewPrim(), workers: 4, braid: 1, levels: 1},
+		{algorithm: generator.NewBacktrack(), workers: 3, braid: 0.5, levels: 2},
+		{algorithm: defaultCave(), workers: 3, levels: 1},
+	}
+
+	for i, testCase := range testCases {
+		t.Run(fmt.Sprintf("#%d", i+1), func(t *testing.T) {
+			t.Parallel()
+
+			data := domain.NewLevelMazeData(testCase.levels, 16, 16, domain.NewCoord(0, 3), domain.NewCoord(15, 12))
+			data.Mask = ringMask(16, 8)
+
+			maze := generateWithDiscard(
+				t,
+				generator.New(
+					testCase.algorithm,
+					generator.WithSeed(uint64(i)),
+					generator.WithWorkers(testCase.workers),
+					generator.WithBraid(testCase.braid),
+				),
+				data,
+			)
+
+			for row := range maze.Cells {
+				for col, tpe := range maze.Cells[row] {
+					if !data.Mask.Allows(data.CoordAt(row, col)) {
+						require.Equal(t, domain.Wall, tpe, "cells outside the mask should stay walls")
+					}
+				}
+			}
+
+			require.True(t, topologyPathExists(maze), "end should be reachable from start around the hole")
+		})
+	}
+}
+
+func TestGenerateMazeWithUnsupportedMask(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		algorithm generator.Algorithm
+	}{
+		{algorithm: generator.NewKruskal()},
+		{algorithm: generator.NewWilson()},
+		{algorithm: generator.NewEller()},
+		{algorithm: generator.NewRecursiveDivision(1)},
+		{algorithm: generator.NewHuntAndKill()},
+		{algorithm: generator.NewAldousBroder()},
+		{algorithm: generator.NewGrowingTree(nil)},
+		{algorithm: generator.NewDungeon(generator.DefaultMinRoomSize, generator.DefaultMaxRoomSize)},
+	}
+
+	for i, testCase := range testCases {
+		t.Run(fmt.Sprintf("#%d", i+1), func(t *testing.T) {
+			t.Parallel()
+
+			gen := generator.New(testCase.algorithm)
+			ch := make(chan domain.CellPaintingData)
+
+			go func() {
+				for range ch {
+				}
+			}()
+
+			data := domain.NewMazeData(10, 10, domain.NewCoord(0, 0), domain.NewCoord(9, 9))
+			data.Mask = ringMask(10, 4)
+
+			_, err := gen.GenerateMaze(context.Background(), data, ch)
+			close(ch)
+
+			require.ErrorAs(t, err, &generator.ErrUnsupportedMask{}, "algorithm should reject masks")
+		})
+	}
+}
+
+func TestGenerateMazeWithIslandMask(t *testing.T) {
+	t.Parallel()
+
+	data := domain.NewMazeData(5, 5, domain.NewCoord(0, 0), domain.NewCoord(0, 4))
+	data.Mask = make(domain.Mask, 5)
+
+	for i := range data.Mask {
+		data.Mask[i] = []bool{true, true, false, true, true}
+	}
+
+	_, err := generator.New(generator.NewPrim(), generator.WithHeadless(true)).
+		GenerateMaze(context.Background(), data, nil)
+	require.ErrorIs(t, err, generator.ErrDisconnectedMask{}, "start and end on separate islands should be rejected")
+}
+
+func TestGenerateMazeWithNarrowMask(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		size    int
+		row     int
+		from    int
+		to      int
+		workers int
+	}{
+		{size: 500, row: 250, from: 240, to: 248, workers: 4},
+		{size: 1000, row: 0, from: 0, to: 2, workers: 3},
+		{size: 300, row: 299, from: 290, to: 299, workers: 8},
+	}
+
+	for i, testCase := range testCases {
+		t.Run(fmt.Sprintf("#%d", i+1), func(t *testing.T) {
+			t.Parallel()
+
+			data := domain.NewMazeData(
+				testCase.size,
+				testCase.size,
+				domain.NewCoord(testCase.row, testCase.from),
+				domain.NewCoord(testCase.row, testCase.to),
+			)
+			data.Mask = make(domain.Mask, testCase.size)
+			data.Mask[testCase.row] = make([]bool, testCase.size)
+
+			for col := testCase.from; col <= testCase.to; col++ {
+				data.Mask[testCase.row][col] = true
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			gen := generator.New(generator.NewPrim(), generator.WithSeed(uint64(i)), generator.WithWorkers(testCase.workers))
+			ch := make(chan domain.CellPaintingData)
+
+			var err error
+
+			go func() {
+				defer close(ch)
+				_, err = gen.GenerateMaze(ctx, data, ch)
+			}()
+
+			senders := make(map[int]struct{})
+			outside := 0
+
+			for cellData := range ch {
+				if cellData.Tpe != domain.Wall && !data.Mask.Allows(domain.NewCoord(cellData.Row, cellData.Col)) {
+					outside++
+				}
+
+				senders[cellData.SenderID] = struct{}{}
+			}
+
+			require.NoError(t, err, "generate maze should return nil error")
+			require.Zero(t, outside, "cells outside the mask shouldn't be carved")
+
+			for id := 1; id <= testCase.workers; id++ {
+				require.Contains(t, senders, id, "every worker should start inside the mask")
+			}
+		})
+	}
+}
+
+func TestGenerateMazeWithMaskedOrigin(t *testing.T) {
+	t.Parallel()
+
+	data := domain.NewMazeData(10, 10, domain.NewCoord(0, 0), domain.NewCoord(9, 9))
+	data.Mask = ringMask(10, 4)
+
+	gen := generator.New(generator.NewPrim(), generator.WithOrigins(domain.NewCoord(5, 5)))
+	ch := make(chan domain.CellPaintingData)
+
+	go func() {
+		for range ch {
+		}
+	}()
+
+	_, err := gen.GenerateMaze(context.Background(), data, ch)
+	close(ch)
+
+	require.ErrorAs(t, err, &generator.ErrInvalidOrigin{}, "origins outside the mask should be rejected")
+}
+
+// designedConstraints returns a wall across the 16x16 maze with a gap at the
+// right edge, a river crossing below it, money in the centre and a passage
+// walled in at the bottom left.
+func designedConstraints() domain.Constraints {
+	constraints := domain.Constraints{
+		domain.NewCoord(7, 7):   domain.NewTerrainConstraint(domain.Money),
+		domain.NewCoord(2, 12):  domain.NewPassageConstraint(),
+		domain.NewCoord(14, 2):  domain.NewPassageConstraint(),
+		domain.NewCoord(13, 2):  domain.NewWallConstraint(),
+		domain.NewCoord(15, 2):  domain.NewWallConstraint(),
+		domain.NewCoord(14, 1):  domain.NewWallConstraint(),
+		domain.NewCoord(14, 3):  domain.NewWallConstraint(),
+		domain.NewCoord(12, 15): domain.NewPassageConstraint(),
+	}
+
+	for col := range 13 {
+		constraints[domain.NewCoord(9, col)] = domain.NewWallConstraint()
+	}
+
+	for col := range 16 {
+		constraints[domain.NewCoord(11, col)] = domain.NewTerrainConstraint(domain.River)
+	}
+
+	return constraints
+}
+
+func TestGenerateMazeWithConstraints(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		algorithm generator.Algorithm
+		topology  domain.Topology
+		workers   int
+		braid     float64
+		biomes    bool
+	}{
+		{algorithm: generator.NewPrim(), topology: domain.Square, workers: 2},
+		{algorithm: generator.NewBacktrack(), topology: domain.Square, workers: 2},
+		{algorithm: generator.NewPrim(), topology: domain.Hex, workers: 3, braid: 1},
+		{algorithm: generator.NewBacktrack(), topology: domain.Torus, workers: 4, braid: 0.5},
+		{algorithm: generator.NewPrim(), topology: domain.Square, workers: 2, biomes: true},
+		{algorithm: generator.NewBacktrack(), topology: domain.Hex, workers: 2, braid: 1, biomes: true},
+	}
+
+	for i, testCase := range testCases {
+		t.Run(fmt.Sprintf("#%d", i+1), func(t *testing.T) {
+			t.Parallel()
+
+			data := domain.NewMazeData(16, 16, domain.NewCoord(0, 0), domain.NewCoord(15, 15))
+			data.Topology = testCase.topology
+			data.Constraints = designedConstraints()
+
+			maze := generateWithDiscard(
+				t,
+				generator.New(
+					testCase.algorithm,
+					generator.WithSeed(uint64(i)),
+					generator.WithWorkers(testCase.workers),
+					generator.WithBraid(testCase.braid),
+					generator.WithBiomes(testCase.biomes),
+				),
+				data,
+			)
+
+			for coord, constraint := range data.Constraints {
+				switch tpe := maze.Cell(coord); constraint.Kind {
+				case domain.FixedWall:
+					require.Equal(t, domain.Wall, tpe, "cell %v should stay a wall", coord)
+				case domain.FixedPassage:
+					require.NotEqual(t, domain.Wall, tpe, "cell %v should be carved", coord)
+				case domain.FixedTerrain:
+					require.Equal(t, constraint.Terrain, tpe, "cell %v should keep its terrain", coord)
+				}
+			}
+
+			require.True(t, topologyPathExists(maze), "end should be reachable from start through the gap")
+		})
+	}
+}
+
+func TestGenerateMazeWithConstraintsOnLevels(t *testing.T) {
+	t.Parallel()
+
+	data := domain.NewLevelMazeData(2, 16, 16, domain.NewCoord(0, 0), domain.NewCoord(15, 15))
+	data.Constraints = designedConstraints()
+
+	maze := generateWithDiscard(t, generator.New(generator.NewBacktrack(), generator.WithSeed(3)), data)
+
+	for coord, constraint := range data.Constraints {
+		if constraint.Kind == domain.FixedWall {
+			require.Equal(t, domain.Wall, maze.Cell(coord), "cell %v should stay a wall", coord)
+		} else {
+			require.NotEqual(t, domain.Wall, maze.Cell(coord), "cell %v should be carved", coord)
+		}
+	}
+
+	require.True(t, topologyPathExists(maze), "end should be reachable from start")
+}
+
+func TestGenerateMazeWithInvalidConstraints(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		constraints domain.Constraints
+		expected    error
+	}{
+		{
+			constraints: domain.Constraints{domain.NewCoord(10, 3): domain.NewWallConstraint()},
+			expected:    generator.NewErrInvalidConstraint(domain.NewCoord(10, 3)),
+		},
+		{
+			constraints: domain.Constraints{domain.NewCoord(9, 9): domain.NewWallConstraint()},
+			expected:    generator.NewErrInvalidConstraint(domain.NewCoord(9, 9)),
+		},
+		{
+			constraints: domain.Constraints{domain.NewCoord(4, 4): domain.NewTerrainConstraint(domain.Path)},
+			expected:    generator.NewErrInvalidConstraint(domain.NewCoord(4, 4)),
+		},
+		{
+			constraints: domain.Constraints{
+				domain.NewCoord(8, 9): domain.NewWallConstraint(),
+				domain.NewCoord(9, 8): domain.NewWallConstraint(),
+			},
+			expected: generator.ErrWalledOff{},
+		},
+	}
+
+	for i, testCase := range testCases {
+		t.Run(fmt.Sprintf("#%d", i+1), func(t *testing.T) {
+			t.Parallel()
+
+			data := domain.NewMazeData(10, 10, domain.NewCoord(0, 0), domain.NewCoord(9, 9))
+			data.Constraints = testCase.constraints
+
+			_, err := generator.New(generator.NewPrim(), generator.WithHeadless(true)).
+				GenerateMaze(context.Background(), data, nil)
+			require.ErrorIs(t, err, testCase.expected, "constraints should be rejected")
+		})
+	}
+}
+
+func TestGenerateMazeWithUnsupportedConstraints(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		algorithm generator.Algorithm
+	}{
+		{algorithm: generator.NewKruskal()},
+		{algorithm: generator.NewWilson()},
+		{algorithm: generator.NewEller()},
+		{algorithm: generator.NewRecursiveDivision(1)},
+		{algorithm: generator.NewHuntAndKill()},
+		{algorithm: generator.NewAldousBroder()},
+		{algorithm: generator.NewGrowingTree(nil)},
+		{algorithm: generator.NewDungeon(generator.DefaultMinRoomSize, generator.DefaultMaxRoomSize)},
+		{algorithm: defaultCave()},
+	}
+
+	for i, testCase := range testCases {
+		t.Run(fmt.Sprintf("#%d", i+1), func(t *testing.T) {
+			t.Parallel()
+
+			data := domain.NewMazeData(10, 10, domain.NewCoord(0, 0), domain.NewCoord(9, 9))
+			data.Constraints = domain.Constraints{domain.NewCoord(5, 5): domain.NewTerrainConstraint(domain.Money)}
+
+			_, err := generator.New(testCase.algorithm, generator.WithHeadless(true)).
+				GenerateMaze(context.Background(), data, nil)
+			require.ErrorAs(t, err, &generator.ErrUnsupportedConstraints{}, "algorithm should reject constraints")
+		})
+	}
+}
+
+func TestGenerateMazeWithCancelledContext(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		algorithm generator.Algorithm
+		biomes    bool
+	}{
+		{algorithm: generator.NewPrim()},
+		{algorithm: generator.NewBacktrack()},
+		{algorithm: generator.NewKruskal()},
+		{algorithm: generator.NewWilson()},
+		{algorithm: generator.NewEller()},
+		{algorithm: generator.NewRecursiveDivision(generator.DefaultMinChamberSize)},
+		{algorithm: generator.NewHuntAndKill()},
+		{algorithm: generator.NewAldousBroder()},
+		{algorithm: generator.NewGrowingTree(nil)},
+		{algorithm: generator.NewDungeon(generator.DefaultMinRoomSize, generator.DefaultMaxRoomSize)},
+		{algorithm: defaultCave()},
+		{algorithm: generator.NewPrim(), biomes: true},
+	}
+
+	for i, testCase := range testCases {
+		t.Run(fmt.Sprintf("#%d", i+1), func(t *testing.T) {
+			t.Parallel()
+
+			data := domain.NewMazeData(51, 51, domain.NewCoord(0, 0), domain.NewCoord(50, 50))
+			gen := generator.New(testCase.algorithm, generator.WithBiomes(testCase.biomes), generator.WithBraid(0.5))
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			// the painter stops reading after a few cells like the one of
+			// an interrupted program
+			ch := make(chan domain.CellPaintingData)
+
+			go func() {
+				for range 10 {
+					<-ch
+				}
+
+				cancel()
+			}()
+
+			_, err := gen.GenerateMaze(ctx, data, ch)
+			require.ErrorIs(t, err, context.Canceled, "generation should be cancelled")
+		})
+	}
+}
+
+func TestGenerateMazeWithTimeout(t *testing.T) {
+	t.Parallel()
+
+	data := domain.NewMazeData(11, 11, domain.NewCoord(0, 0), domain.NewCoord(10, 10))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	// nobody reads the channel, so generation can only stop on the timeout
+	_, err := generator.New(generator.NewPrim()).GenerateMaze(ctx, data, make(chan domain.CellPaintingData))
+	require.ErrorIs(t, err, context.DeadlineExceeded, "generation should time out")
+}
+
+func TestGenerateMazeHeadless(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		algorithm generator.Algorithm
+		data      domain.MazeData
+	}{
+		{
+			algorithm: generator.NewPrim(),
+			data:      domain.NewMazeData(21, 31, domain.NewCoord(0, 0), domain.NewCoord(20, 30)),
+		},
+		{
+			algorithm: generator.NewWilson(),
+			data:      domain.NewMazeData(21, 31, domain.NewCoord(0, 0), domain.NewCoord(20, 30)),
+		},
+		{
+			algorithm: generator.NewHuntAndKill(),
+			data:      domain.NewMazeData(15, 15, domain.NewCoord(0, 0), domain.NewCoord(14, 14)),
+		},
+		{
+			algorithm: generator.NewBacktrack(),
+			data:      domain.NewLevelMazeData(3, 9, 9, domain.NewCoord(0, 0), domain.NewCoord(8, 8)),
+		},
+	}
+
+	for i, testCase := range testCases {
+		t.Run(fmt.Sprintf("#%d", i+1), func(t *testing.T) {
+			t.Parallel()
+
+			opts := []generator.Option{generator.WithSeed(17), generator.WithBraid(0.5), generator.WithBiomes(true)}
+			drawn := generateWithDiscard(t, generator.New(testCase.algorithm, opts...), testCase.data)
+
+			// nobody reads the channel, so any drawing event would block
+			headless, err := generator.New(testCase.algorithm, append(opts, generator.WithHeadless(true))...).
+				GenerateMaze(context.Background(), testCase.data, make(chan domain.CellPaintingData))
+
+			require.NoError(t, err, "generate maze should return nil error")
+			require.Equal(t, drawn.Cells, headless.Cells, "headless maze should be the same as the drawn one")
+			require.Equal(t, drawn.Repaired, headless.Repaired, "headless maze should open the same walls")
+		})
+	}
+}
+
+func TestParseDifficulty(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		str      string
+		expected generator.Difficulty
+	}{
+		{
+			str:      "cost 120, decisions 8, dead-ends 0.2",
+			expected: generator.Difficulty{PathCost: 120, Decisions: 8, DeadEnds: 0.2},
+		},
+		{
+			str:      "Decisions 3",
+			expected: generator.Difficulty{Decisions: 3},
+		},
+		{
+			str:      " dead-ends 1 ,cost 0",
+			expected: generator.Difficulty{DeadEnds: 1},
+		},
+	}
+
+	for i, testCase := range testCases {
+		t.Run(fmt.Sprintf("#%d", i+1), func(t *testing.T) {
+			t.Parallel()
+
+			difficulty, err := generator.ParseDifficulty(testCase.str)
+
+			require.NoError(t, err, "difficulty should be valid")
+			require.Equal(t, testCase.expected, difficulty, "invalid difficulty")
+		})
+	}
+}
+
+func TestParseInvalidDifficulty(t *testing.T) {
+	t.Parallel()
+
+	for i, str := range []string{"", "cost", "cost -1", "decisions 2.5", "dead-ends 1.5", "length 10", "cost 10 decisions 2"} {
+		t.Run(fmt.Sprintf("#%d", i+1), func(t *testing.T) {
+			t.Parallel()
+
+			_, err := generator.ParseDifficulty(str)
+			require.ErrorAs(t, err, &generator.ErrInvalidDifficulty{}, "difficulty should be invalid")
+		})
+	}
+}
+
+func TestMeasure(t *testing.T) {
+	t.Parallel()
+
+	data := domain.NewMazeData(5, 5, domain.NewCoord(0, 0), domain.NewCoord(4, 4))
+	maze := domain.NewMaze(data, [][]domain.CellType{
+		{1, 1, 1, 1, 1},
+		{1, 0, 0, 0, 1},
+		{1, 1, 0, 0, 1},
+		{0, 0, 0, 0, 1},
+		{1, 1, 1, 0, 1},
+	})
+
+	score, err := generator.Measure(context.Background(), maze)
+
+	require.NoError(t, err, "measure should return nil error")
+	// the path goes along the top row and the right column through 8 passages
+	// costing 3, only the start is a fork, and (2, 1), (4, 0) and (4, 2) are
+	// dead ends of 13 passages besides the start and the end
+	require.Equal(t, 24, score.PathCost, "invalid path cost")
+	require.Equal(t, 1, score.Decisions, "invalid number of decisions")
+	require.InDelta(t, 3.0/13, score.DeadEnds, 1e-9, "invalid share of dead ends")
+}
+
+func TestGenerateMazeWithDifficulty(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		algorithm  generator.Algorithm
+		difficulty generator.Difficulty
+	}{
+		{
+			algorithm:  generator.NewPrim(),
+			difficulty: generator.Difficulty{PathCost: 86},
+		},
+		{
+			algorithm:  generator.NewBacktrack(),
+			difficulty: generator.Difficulty{Decisions: 27},
+		},
+		{
+			algorithm:  generator.NewKruskal(),
+			difficulty: generator.Difficulty{PathCost: 150, DeadEnds: 0.14},
+		},
+	}
+
+	for i, testCase := range testCases {
+		t.Run(fmt.Sprintf("#%d", i+1), func(t *testing.T) {
+			t.Parallel()
+
+			data := domain.NewMazeData(15, 15, domain.NewCoord(0, 0), domain.NewCoord(14, 14))
+			gen := generator.New(testCase.algorithm, generator.WithSeed(5), generator.WithDifficulty(testCase.difficulty))
+			maze := generateWithDiscard(t, gen, data)
+
+			score, err := generator.Measure(context.Background(), maze)
+			require.NoError(t, err, "measure should return nil error")
+			require.True(t, testCase.difficulty.Met(score), "maze should be at least as difficult as %s", testCase.difficulty)
+			require.NotEqual(t, uint64(5), gen.Seed(), "the maze of the original seed is too easy")
+
+			// the seed of the accepted maze reproduces it without the difficulty
+			again := generateWithDiscard(t, generator.New(testCase.algorithm, generator.WithSeed(gen.Seed())), data)
+			require.Equal(t, maze.Cells, again.Cells, "seed should reproduce the maze")
+		})
+	}
+}
+
+func TestGenerateMazeWithUnreachableDifficulty(t *testing.T) {
+	t.Parallel()
+
+	data := domain.NewMazeData(5, 5, domain.NewCoord(0, 0), domain.NewCoord(4, 4))
+	gen := generator.New(generator.NewPrim(), generator.WithDifficulty(generator.Difficulty{PathCost: 1000}))
+
+	_, err := gen.GenerateMaze(context.Background(), data, make(chan domain.CellPaintingData))
+	require.ErrorAs(t, err, &generator.ErrDifficultyNotMet{}, "a small maze can't have such a long path")
+}
+
+func BenchmarkGenerateMaze(b *testing.B) {
+	benchmarks := []struct {
+		name      string
+		algorithm generator.Algorithm
+	}{
+		{name: "prim", algorithm: generator.NewPrim()},
+		{name: "backtrack", algorithm: generator.NewBacktrack()},
+		{name: "kruskal", algorithm: generator.NewKruskal()},
+		{name: "wilson", algorithm: generator.NewWilson()},
+		{name: "eller", algorithm: generator.NewEller()},
+		{name: "division", algorithm: generator.NewRecursiveDivision(generator.DefaultMinChamberSize)},
+		{name: "hunt-and-kill", algorithm: generator.NewHuntAndKill()},
+		{name: "aldous-broder", algorithm: generator.NewAldousBroder()},
+		{name: "growing-tree", algorithm: generator.NewGrowingTree(nil)},
+		{name: "dungeon", algorithm: generator.NewDungeon(generator.DefaultMinRoomSize, generator.DefaultMaxRoomSize)},
+		{name: "cave", algorithm: defaultCave()},
+	}
+
+	for _, size := range []int{101, 1001} {
+		data := domain.NewMazeData(size, size, domain.NewCoord(0, 0), domain.NewCoord(size-1, size-1))
+
+		for _, bm := range benchmarks {
+			b.Run(fmt.Sprintf("%s/%dx%d", bm.name, size, size), func(b *testing.B) {
+				gen := generator.New(bm.algorithm, generator.WithSeed(1), generator.WithHeadless(true))
+
+				b.ReportAllocs()
+				b.ResetTimer()
+
+				for range b.N {
+					if _, err := gen.GenerateMaze(context.Background(), data, nil); err != nil {
+						b.Fatal(err)
+					}
+				}
+
+				b.ReportMetric(float64(size*size)*float64(b.N)/b.Elapsed().Seconds(), "cells/s")
+			})
+		}
+	}
+}
+
+func TestRegistrySupported(t *testing.T) {
+	t.Parallel()
+
+	square := domain.NewMazeData(10, 10, domain.NewCoord(0, 0), domain.NewCoord(9, 9))
+
+	hex := square
+	hex.Topology = domain.Hex
+
+	evenTorus := square
+	evenTorus.Topology = domain.Torus
+
+	oddTorus := domain.NewMazeData(9, 9, domain.NewCoord(0, 0), domain.NewCoord(8, 8))
+	oddTorus.Topology = domain.Torus
+
+	levels := square
+	levels.Levels = 3
+
+	masked := square
+	masked.Mask = make(domain.Mask, square.Height)
+
+	for i := range masked.Mask {
+		masked.Mask[i] = make([]bool, square.Width)
+		for j := range masked.Mask[i] {
+			masked.Mask[i][j] = i < 5 || j > 4
+		}
+	}
+
+	constrained := square
+	constrained.Constraints = domain.Constraints{domain.NewCoord(5, 5): domain.NewWallConstraint()}
+
+	testCases := []struct {
+		data     domain.MazeData
+		expected []string
+	}{
+		{
+			data: square,
+			expected: []string{
+				"prim", "backtrack", "kruskal", "wilson", "eller", "division",
+				"hunt-and-kill", "aldous-broder", "growing-tree", "dungeon", "cave",
+			},
+		},
+		{
+			data: hex,
+			expected: []string{
+				"prim", "backtrack", "kruskal", "wilson", "eller", "division",
+				"hunt-and-kill", "aldous-broder", "growing-tree", "dungeon",
+			},
+		},
+		{
+			data: evenTorus,
+			expected: []string{
+				"prim", "backtrack", "kruskal", "wilson", "hunt-and-kill", "aldous-broder", "growing-tree", "cave",
+			},
+		},
+		{
+			data:     oddTorus,
+			expected: []string{"prim", "backtrack", "cave"},
+		},
+		{
+			data:     levels,
+			expected: []string{"prim", "backtrack"},
+		},
+		{
+			data:     masked,
+			expected: []string{"prim", "backtrack", "cave"},
+		},
+		{
+			data:     constrained,
+			expected: []string{"prim", "backtrack"},
+		},
+	}
+
+	for i, testCase := range testCases {
+		t.Run(fmt.Sprintf("#%d", i+1), func(t *testing.T) {
+			t.Parallel()
+
+			names := make([]string, 0)
+			for _, entry := range generator.NewRegistry().Supported(testCase.data) {
+				names = append(names, entry.Name)
+			}
+
+			require.Equal(t, testCase.expected, names, "algorithms should be equal")
+
+			// every supported algorithm should generate the maze
+			for _, name := range names {
+				algo, err := generator.NewRegistry().Create(name, nil)
+				require.NoError(t, err, "algorithm should be created without error")
+
+				_, err = generator.New(algo, generator.WithSeed(1)).GenerateMaze(context.Background(), testCase.data, nil)
+				require.NoError(t, err, "maze should be generated without error by %s", name)
+			}
+		})
+	}
+}
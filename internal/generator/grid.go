@@ -0,0 +1,271 @@
+package generator
+
+import (
+	"slices"
+	"time"
+
+	"github.com/LLIEPJIOK/mazegenerator/internal/domain"
+)
+
+// stairsChance is the chance a growing maze goes to the adjacent floor from
+// a cell, so floors are mostly connected by passages on them.
+const stairsChance = 0.1
+
+// grid is the shape of the generated maze. Floors of a multi-level maze are
+// stacked in cells the same way as in domain.Maze.
+type grid struct {
+	height      int
+	width       int
+	levels      int
+	topology    domain.Topology
+	mask        domain.Mask
+	constraints domain.Constraints
+}
+
+func newGrid(data domain.MazeData) grid {
+	return grid{
+		height:      data.Height,
+		width:       data.Width,
+		levels:      data.Floors(),
+		topology:    data.Topology,
+		mask:        data.Mask,
+		constraints: data.Constraints,
+	}
+}
+
+func (g grid) data() domain.MazeData {
+	return domain.MazeData{
+		Height:      g.height,
+		Width:       g.width,
+		Topology:    g.topology,
+		Levels:      g.levels,
+		Mask:        g.mask,
+		Constraints: g.constraints,
+	}
+}
+
+// newCells returns walls of the grid. Rows share one flat slice, so the cells
+// are allocated at once and lie next to each other in memory.
+func (g grid) newCells() [][]domain.CellType {
+	return newFlatCells[domain.CellType](g.levels*g.height, g.width)
+}
+
+// newFlatCells returns a rows x cols matrix backed by one flat slice.
+func newFlatCells[T any](rows, cols int) [][]T {
+	flat := make([]T, rows*cols)
+	cells := make([][]T, rows)
+
+	for i := range cells {
+		cells[i] = flat[i*cols : (i+1)*cols : (i+1)*cols]
+	}
+
+	return cells
+}
+
+func (g grid) inside(coord domain.Coord) bool {
+	return g.data().Contains(coord)
+}
+
+// requireLattice returns an error for topologies without a lattice of rooms,
+// for multi-level mazes, masked and constrained ones. Rooms tile a torus only
+// if both of its dimensions are even, otherwise rooms on opposite edges would
+// touch.
+func (g grid) requireLattice() error {
+	if !g.topology.HasLattice() {
+		return NewErrUnsupportedTopology(g.topology)
+	}
+
+	if g.levels > 1 {
+		return NewErrUnsupportedLevels(g.levels)
+	}
+
+	if g.mask != nil {
+		return ErrUnsupportedMask{}
+	}
+
+	if len(g.constraints) != 0 {
+		return ErrUnsupportedConstraints{}
+	}
+
+	if g.topology.Wraps() && (g.height%2 != 0 || g.width%2 != 0) {
+		return NewErrOddTorus(g.height, g.width)
+	}
+
+	return nil
+}
+
+// requireBorders returns an error for grids which wrap, so algorithms
+// relying on the outer border of the grid can reject them.
+func (g grid) requireBorders() error {
+	if g.topology.Wraps() {
+		return NewErrUnsupportedTopology(g.topology)
+	}
+
+	return nil
+}
+
+// requireSquareFloor returns an error for grids whose cells aren't squares,
+// for multi-level and constrained ones, so algorithms looking at all 8 cells
+// around a cell can reject them.
+func (g grid) requireSquareFloor() error {
+	if g.topology != domain.Square && g.topology != domain.Torus {
+		return NewErrUnsupportedTopology(g.topology)
+	}
+
+	if g.levels > 1 {
+		return NewErrUnsupportedLevels(g.levels)
+	}
+
+	if len(g.constraints) != 0 {
+		return ErrUnsupportedConstraints{}
+	}
+
+	return nil
+}
+
+// maxNeighbours bounds the number of neighbours of a cell, so buffers of
+// this capacity hold them without growing.
+const maxNeighbours = 8
+
+func (g grid) neighbours(coord domain.Coord) []domain.Coord {
+	return g.appendNeighbours(make([]domain.Coord, 0, maxNeighbours), coord)
+}
+
+// appendNeighbours appends cells adjacent to the coord to dst. Loops which
+// don't keep the neighbours pass a buffer on the stack, so no cell costs an
+// allocation.
+func (g grid) appendNeighbours(dst []domain.Coord, coord domain.Coord) []domain.Coord {
+	return g.data().AppendNeighbours(dst, coord)
+}
+
+// grows reports whether a maze may grow from the cell to the adjacent one.
+// It never goes to cells fixed as walls and goes to adjacent floors with
+// stairsChance only.
+func (g grid) grows(from, to domain.Coord, rnd *randomSource) bool {
+	if g.constraints.Wall(to) {
+		return false
+	}
+
+	return from.Level == to.Level || rnd.Float64() < stairsChance
+}
+
+// terrain returns the type the cell is carved with: its fixed terrain or a
+// random one.
+func (g grid) terrain(coord domain.Coord, rnd *randomSource) domain.CellType {
+	if tpe, ok := g.constraints.Terrain(coord); ok {
+		return tpe
+	}
+
+	return rnd.cellType()
+}
+
+// growthNeighbours returns cells a maze may grow to from the coord.
+func (g grid) growthNeighbours(coord domain.Coord, rnd *randomSource) []domain.Coord {
+	return slices.DeleteFunc(g.neighbours(coord), func(next domain.Coord) bool {
+		return !g.grows(coord, next, rnd)
+	})
+}
+
+func (g grid) row(coord domain.Coord) int {
+	return coord.Level*g.height + coord.Row
+}
+
+func (g grid) cell(cells [][]domain.CellType, coord domain.Coord) domain.CellType {
+	return cells[g.row(coord)][coord.Col]
+}
+
+// appendPassageNeighbours appends carved cells adjacent to the coord to dst.
+func (g grid) appendPassageNeighbours(
+	dst []domain.Coord,
+	cells [][]domain.CellType,
+	coord domain.Coord,
+) []domain.Coord {
+	var buf [maxNeighbours]domain.Coord
+
+	for _, neighbour := range g.appendNeighbours(buf[:0], coord) {
+		if g.cell(cells, neighbour) != domain.Wall {
+			dst = append(dst, neighbour)
+		}
+	}
+
+	return dst
+}
+
+// countPassages returns the number of carved cells adjacent to the coord.
+func (g grid) countPassages(cells [][]domain.CellType, coord domain.Coord) int {
+	var buf [maxNeighbours]domain.Coord
+
+	cnt := 0
+
+	for _, neighbour := range g.appendNeighbours(buf[:0], coord) {
+		if g.cell(cells, neighbour) != domain.Wall {
+			cnt++
+		}
+	}
+
+	return cnt
+}
+
+func (g grid) set(
+	cells [][]domain.CellType,
+	coord domain.Coord,
+	tpe domain.CellType,
+	drawingChan chan<- cell,
+	delay time.Duration,
+) {
+	cells[g.row(coord)][coord.Col] = tpe
+	draw(drawingChan, newCell(g.row(coord), coord.Col, tpe, delay))
+}
+
+// carve makes the cell a passage of the given type. Passages right above and
+// below it are joined with it by stairs, so every two carved cells on
+// adjacent floors are connected.
+func (g grid) carve(
+	cells [][]domain.CellType,
+	coord domain.Coord,
+	tpe domain.CellType,
+	drawingChan chan<- cell,
+	delay time.Duration,
+) {
+	var buf [maxNeighbours]domain.Coord
+
+	for _, neighbour := range g.appendNeighbours(buf[:0], coord) {
+		if neighbour.Level != coord.Level && g.cell(cells, neighbour) != domain.Wall {
+			tpe = domain.Stairs
+
+			g.set(cells, neighbour, domain.Stairs, drawingChan, delay)
+		}
+	}
+
+	g.set(cells, coord, tpe, drawingChan, delay)
+}
+
+// fill turns the cell into a wall. Stairs which led only to it become
+// passages.
+func (g grid) fill(cells [][]domain.CellType, coord domain.Coord, drawingChan chan<- cell, delay time.Duration) {
+	g.set(cells, coord, domain.Wall, drawingChan, delay)
+
+	var buf [maxNeighbours]domain.Coord
+
+	for _, neighbour := range g.appendNeighbours(buf[:0], coord) {
+		if neighbour.Level == coord.Level || g.cell(cells, neighbour) != domain.Stairs {
+			continue
+		}
+
+		if !g.hasStairs(cells, neighbour) {
+			g.set(cells, neighbour, domain.Passage, drawingChan, delay)
+		}
+	}
+}
+
+func (g grid) hasStairs(cells [][]domain.CellType, coord domain.Coord) bool {
+	var buf [maxNeighbours]domain.Coord
+
+	for _, neighbour := range g.appendNeighbours(buf[:0], coord) {
+		if neighbour.Level != coord.Level && g.cell(cells, neighbour) == domain.Stairs {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,271 @@
+package generator
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/LLIEPJIOK/mazegenerator/internal/domain"
+	"github.com/LLIEPJIOK/mazegenerator/internal/registry"
+)
+
+type Selection int
+
+const (
+	SelectNewest Selection = iota
+	SelectOldest
+	SelectRandom
+)
+
+func (s Selection) String() string {
+	switch s {
+	case SelectNewest:
+		return "newest"
+	case SelectOldest:
+		return "oldest"
+	case SelectRandom:
+		return "random"
+	}
+
+	return ""
+}
+
+func parseSelection(str string) (Selection, bool) {
+	for _, sel := range []Selection{SelectNewest, SelectOldest, SelectRandom} {
+		if sel.String() == str {
+			return sel, true
+		}
+	}
+
+	return 0, false
+}
+
+type WeightedSelection struct {
+	Selection Selection
+	Weight    int
+}
+
+func NewWeightedSelection(sel Selection, weight int) WeightedSelection {
+	return WeightedSelection{
+		Selection: sel,
+		Weight:    weight,
+	}
+}
+
+// Strategy chooses the next active cell of the growing tree. Newest gives
+// Backtrack-like long corridors, random gives Prim-like short ones, and
+// mixing them tunes the corridor length.
+type Strategy []WeightedSelection
+
+const DefaultStrategy = "50% newest, 50% random"
+
+// defaultStrategy is DefaultStrategy parsed.
+func defaultStrategy() Strategy {
+	return Strategy{
+		NewWeightedSelection(SelectNewest, 50),
+		NewWeightedSelection(SelectRandom, 50),
+	}
+}
+
+// ParseStrategy parses strategies like "newest" or "75% newest, 25% random".
+// Weights are relative, so the percent sign is optional and parts without
+// a weight count as 1.
+func ParseStrategy(str string) (Strategy, error) {
+	strategy := make(Strategy, 0)
+
+	for _, part := range strings.Split(str, ",") {
+		fields := strings.Fields(part)
+		weight := 1
+
+		switch len(fields) {
+		case 1:
+		case 2:
+			var err error
+
+			weight, err = strconv.Atoi(strings.TrimSuffix(fields[0], "%"))
+			if err != nil || weight <= 0 {
+				return nil, NewErrInvalidStrategy(str)
+			}
+
+			fields = fields[1:]
+		default:
+			return nil, NewErrInvalidStrategy(str)
+		}
+
+		sel, ok := parseSelection(strings.ToLower(fields[0]))
+		if !ok {
+			return nil, NewErrInvalidStrategy(str)
+		}
+
+		strategy = append(strategy, NewWeightedSelection(sel, weight))
+	}
+
+	return strategy, nil
+}
+
+// String prints weights in percent of their total, like "25% newest, 75%
+// random". If some weight isn't a whole percent, weights are printed as they
+// are, like "1 newest, 2 random", since ParseStrategy reads both back.
+func (s Strategy) String() string {
+	total := 0
+
+	for _, sel := range s {
+		total += sel.Weight
+	}
+
+	percents := total > 0
+
+	for _, sel := range s {
+		if percents && sel.Weight*100%total != 0 {
+			percents = false
+		}
+	}
+
+	parts := make([]string, len(s))
+
+	for i, sel := range s {
+		if percents {
+			parts[i] = fmt.Sprintf("%d%% %s", sel.Weight*100/total, sel.Selection)
+		} else {
+			parts[i] = fmt.Sprintf("%d %s", sel.Weight, sel.Selection)
+		}
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+func (s Strategy) selection(rnd *randomSource) Selection {
+	total := 0
+
+	for _, sel := range s {
+		total += sel.Weight
+	}
+
+	r := rnd.IntN(total)
+
+	for _, sel := range s {
+		if r < sel.Weight {
+			return sel.Selection
+		}
+
+		r -= sel.Weight
+	}
+
+	return SelectNewest
+}
+
+// next returns the index of the next active cell from n cells.
+func (s Strategy) next(n int, rnd *randomSource) int {
+	switch s.selection(rnd) {
+	case SelectNewest:
+		return n - 1
+	case SelectOldest:
+		return 0
+	case SelectRandom:
+		return rnd.IntN(n)
+	}
+
+	return n - 1
+}
+
+// GrowingTree keeps a list of active rooms. It takes a room from the list
+// using the strategy, joins it with a random unvisited neighbour which becomes
+// active, or removes the room from the list if there are no such neighbours.
+// It grows a single tree from the start, since trees from other origins would
+// be joined into loops by the merge.
+type GrowingTree struct {
+	strategy Strategy
+}
+
+// NewGrowingTree creates a growing tree with the strategy, or with
+// DefaultStrategy if the strategy is empty.
+func NewGrowingTree(strategy Strategy) *GrowingTree {
+	if len(strategy) == 0 {
+		strategy = defaultStrategy()
+	}
+
+	return &GrowingTree{
+		strategy: strategy,
+	}
+}
+
+func (g *GrowingTree) singleOrigin() {}
+
+func (g *GrowingTree) createMazeCellsFromCoord(
+	ctx context.Context,
+	grd grid,
+	start domain.Coord,
+	rnd *randomSource,
+	drawingChan chan<- cell,
+) ([][]domain.CellType, error) {
+	if err := grd.requireLattice(); err != nil {
+		return nil, err
+	}
+
+	cells := grd.newCells()
+
+	carve := func(coord domain.Coord) {
+		tpe := rnd.cellType()
+		cells[coord.Row][coord.Col] = tpe
+		draw(drawingChan, newCell(coord.Row, coord.Col, tpe, drawingDelay))
+	}
+
+	carve(start)
+
+	active := []domain.Coord{start}
+
+	var buf [maxNeighbours]domain.Coord
+
+	for len(active) != 0 {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		id := g.strategy.next(len(active), rnd)
+		cur := active[id]
+
+		unvisited := make([]domain.Coord, 0, grd.topology.Directions())
+
+		for _, neighbour := range grd.appendRoomNeighbours(buf[:0], cur) {
+			if cells[neighbour.Row][neighbour.Col] == domain.Wall {
+				unvisited = append(unvisited, neighbour)
+			}
+		}
+
+		if len(unvisited) == 0 {
+			active = slices.Delete(active, id, id+1)
+			continue
+		}
+
+		next := unvisited[rnd.IntN(len(unvisited))]
+		carve(grd.between(cur, next))
+		carve(next)
+
+		active = append(active, next)
+	}
+
+	return cells, nil
+}
+
+func growingTreeEntry() registry.Entry[Algorithm] {
+	return registry.Entry[Algorithm]{
+		Name:        "growing-tree",
+		Description: "growing tree algorithm with a tunable cell selection strategy",
+		Params: []registry.Param{
+			registry.NewParam(
+				"strategy",
+				`cell selection strategy: newest, oldest, random or a mix like "75% newest, 25% random"`,
+				DefaultStrategy,
+				func(value string) (any, error) { return ParseStrategy(value) },
+			),
+		},
+		Supports: supportsLattice,
+		New: func(values registry.Values) Algorithm {
+			strategy, _ := values["strategy"].(Strategy)
+
+			return NewGrowingTree(strategy)
+		},
+	}
+}
@@ -0,0 +1,132 @@
+package generator
+
+import (
+	"context"
+
+	"github.com/LLIEPJIOK/mazegenerator/internal/domain"
+	"github.com/LLIEPJIOK/mazegenerator/internal/registry"
+)
+
+// HuntAndKill walks randomly into unvisited rooms until it gets stuck, then
+// hunts for the first unvisited room next to the maze and continues from it.
+// Unlike Backtrack it keeps no stack. The maze is built once from the start,
+// because united mazes would have loops and shorter corridors.
+type HuntAndKill struct{}
+
+func NewHuntAndKill() *HuntAndKill {
+	return &HuntAndKill{}
+}
+
+func (h *HuntAndKill) singleOrigin() {}
+
+// appendNeighbours appends visited or unvisited rooms next to the room to
+// dst, so loops can reuse a buffer.
+func (h *HuntAndKill) appendNeighbours(
+	dst []domain.Coord,
+	grd grid,
+	room domain.Coord,
+	cells [][]domain.CellType,
+	visited bool,
+) []domain.Coord {
+	from := len(dst)
+	dst = grd.appendRoomNeighbours(dst, room)
+	res := dst[:from]
+
+	// rooms are filtered in place, res never overtakes them
+	for _, neighbour := range dst[from:] {
+		if (cells[neighbour.Row][neighbour.Col] != domain.Wall) == visited {
+			res = append(res, neighbour)
+		}
+	}
+
+	return res
+}
+
+// hunt returns the first of the rooms which is unvisited and has a visited
+// neighbour together with that neighbour.
+func (h *HuntAndKill) hunt(
+	grd grid,
+	rooms []domain.Coord,
+	cells [][]domain.CellType,
+	rnd *randomSource,
+) (room, neighbour domain.Coord, ok bool) {
+	var buf [maxNeighbours]domain.Coord
+
+	for _, room = range rooms {
+		if cells[room.Row][room.Col] != domain.Wall {
+			continue
+		}
+
+		visited := h.appendNeighbours(buf[:0], grd, room, cells, true)
+		if len(visited) != 0 {
+			return room, visited[rnd.IntN(len(visited))], true
+		}
+	}
+
+	return domain.Coord{}, domain.Coord{}, false
+}
+
+func (h *HuntAndKill) createMazeCellsFromCoord(
+	ctx context.Context,
+	grd grid,
+	start domain.Coord,
+	rnd *randomSource,
+	drawingChan chan<- cell,
+) ([][]domain.CellType, error) {
+	if err := grd.requireLattice(); err != nil {
+		return nil, err
+	}
+
+	cells := grd.newCells()
+
+	carve := func(coord domain.Coord) {
+		tpe := rnd.cellType()
+		cells[coord.Row][coord.Col] = tpe
+		draw(drawingChan, newCell(coord.Row, coord.Col, tpe, drawingDelay))
+	}
+
+	carve(start)
+
+	rooms, first := grd.rooms(start), 0
+
+	var buf [maxNeighbours]domain.Coord
+
+	for cur, ok := start, true; ok; {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		unvisited := h.appendNeighbours(buf[:0], grd, cur, cells, false)
+
+		if len(unvisited) != 0 {
+			next := unvisited[rnd.IntN(len(unvisited))]
+			carve(grd.between(cur, next))
+			carve(next)
+
+			cur = next
+
+			continue
+		}
+
+		var prev domain.Coord
+
+		// rooms before the first unvisited one stay visited, so hunts skip them
+		for first < len(rooms) && cells[rooms[first].Row][rooms[first].Col] != domain.Wall {
+			first++
+		}
+
+		cur, prev, ok = h.hunt(grd, rooms[first:], cells, rnd)
+		if ok {
+			carve(grd.between(prev, cur))
+			carve(cur)
+		}
+	}
+
+	return cells, nil
+}
+
+func huntAndKillEntry() registry.Entry[Algorithm] {
+	return plainEntry("hunt-and-kill", "hunt-and-kill algorithm, long corridors with low memory use", supportsLattice, func() Algorithm {
+		return NewHuntAndKill()
+	})
+}
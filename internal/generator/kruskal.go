@@ -0,0 +1,109 @@
+package generator
+
+import (
+	"context"
+
+	"github.com/LLIEPJIOK/mazegenerator/internal/domain"
+	"github.com/LLIEPJIOK/mazegenerator/internal/registry"
+)
+
+type kruskalEdge struct {
+	wall   domain.Coord
+	first  domain.Coord
+	second domain.Coord
+}
+
+func newKruskalEdge(wall, first, second domain.Coord) kruskalEdge {
+	return kruskalEdge{
+		wall:   wall,
+		first:  first,
+		second: second,
+	}
+}
+
+// Kruskal treats cells on the same lattice as the start cell as rooms and
+// the cells between them as walls, then joins rooms through randomly ordered
+// walls while they belong to different sets. The tree is built once from the
+// start, because united trees would have loops.
+type Kruskal struct{}
+
+func NewKruskal() *Kruskal {
+	return &Kruskal{}
+}
+
+func (k *Kruskal) singleOrigin() {}
+
+func kruskalEdges(grd grid, start domain.Coord) []kruskalEdge {
+	edges := make([]kruskalEdge, 0)
+
+	var buf [maxNeighbours]domain.Coord
+
+	for _, room := range grd.rooms(start) {
+		for _, neighbour := range grd.appendRoomNeighbours(buf[:0], room) {
+			// every edge is added once, from the room which comes first
+			if neighbour.Row < room.Row || neighbour.Row == room.Row && neighbour.Col < room.Col {
+				continue
+			}
+
+			edges = append(edges, newKruskalEdge(grd.between(room, neighbour), room, neighbour))
+		}
+	}
+
+	return edges
+}
+
+func (k *Kruskal) createMazeCellsFromCoord(
+	ctx context.Context,
+	grd grid,
+	start domain.Coord,
+	rnd *randomSource,
+	drawingChan chan<- cell,
+) ([][]domain.CellType, error) {
+	if err := grd.requireLattice(); err != nil {
+		return nil, err
+	}
+
+	cells := grd.newCells()
+
+	carve := func(coord domain.Coord) {
+		if cells[coord.Row][coord.Col] != domain.Wall {
+			return
+		}
+
+		tpe := rnd.cellType()
+		cells[coord.Row][coord.Col] = tpe
+		draw(drawingChan, newCell(coord.Row, coord.Col, tpe, drawingDelay))
+	}
+
+	edges := kruskalEdges(grd, start)
+	rnd.Shuffle(len(edges), func(i, j int) {
+		edges[i], edges[j] = edges[j], edges[i]
+	})
+
+	sets := newUnionFind(grd.height * grd.width)
+
+	for _, edge := range edges {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		if !sets.union(edge.first.Row*grd.width+edge.first.Col, edge.second.Row*grd.width+edge.second.Col) {
+			continue
+		}
+
+		carve(edge.first)
+		carve(edge.wall)
+		carve(edge.second)
+	}
+
+	// the start cell has no edges if it is the only room
+	carve(start)
+
+	return cells, nil
+}
+
+func kruskalEntry() registry.Entry[Algorithm] {
+	return plainEntry("kruskal", "Kruskal's algorithm, many short dead ends", supportsLattice, func() Algorithm {
+		return NewKruskal()
+	})
+}
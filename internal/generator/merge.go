@@ -0,0 +1,213 @@
+package generator
+
+import (
+	"context"
+
+	"github.com/LLIEPJIOK/mazegenerator/internal/domain"
+)
+
+// MergeStrategy settles conflicts between partial mazes which carved the same
+// cell.
+type MergeStrategy int
+
+const (
+	// MergeRandom takes the cell from a random partial maze.
+	MergeRandom MergeStrategy = iota
+	// MergeCheapest takes the terrain with the lowest cost.
+	MergeCheapest
+	// MergeStartSide prefers the maze grown from the start, then the one
+	// grown from the end and then the others in the order of origins.
+	MergeStartSide
+	// MergeSeam splits the maze into regions of the nearest origin and keeps
+	// each region's own maze. Partial mazes are united only along the seams
+	// between regions.
+	MergeSeam
+	// MergeInterleave alternates partial mazes in conflicting cells by the
+	// XOR of the cell coordinates, so conflicts are settled without randomness.
+	MergeInterleave
+)
+
+func (s MergeStrategy) String() string {
+	switch s {
+	case MergeRandom:
+		return "random"
+	case MergeCheapest:
+		return "cheapest"
+	case MergeStartSide:
+		return "start-side"
+	case MergeSeam:
+		return "seam"
+	case MergeInterleave:
+		return "interleave"
+	}
+
+	return ""
+}
+
+type merger struct {
+	strategy MergeStrategy
+	partials []domain.Maze
+	regions  [][]int
+	rnd      *randomSource
+}
+
+func newMerger(strategy MergeStrategy, partials []domain.Maze, origins []domain.Coord, rnd *randomSource) *merger {
+	m := &merger{
+		strategy: strategy,
+		partials: partials,
+		rnd:      rnd,
+	}
+
+	if strategy == MergeSeam {
+		m.regions = regions(partials[0].Data, origins)
+	}
+
+	return m
+}
+
+// regions assigns every cell to the nearest origin, ties go to the earlier one.
+func regions(data domain.MazeData, origins []domain.Coord) [][]int {
+	res := newFlatCells[int](data.Rows(), data.Width)
+
+	for i := range data.Rows() {
+		for j := range data.Width {
+			bestDist := data.Height + data.Width + data.Floors()
+
+			for k, origin := range origins {
+				if dist := data.Distance(data.CoordAt(i, j), origin); dist < bestDist {
+					res[i][j], bestDist = k, dist
+				}
+			}
+		}
+	}
+
+	return res
+}
+
+func (m *merger) onSeam(row, col int) bool {
+	data := m.partials[0].Data
+
+	var buf [maxNeighbours]domain.Coord
+
+	for _, neighbour := range data.AppendNeighbours(buf[:0], data.CoordAt(row, col)) {
+		if m.regions[data.CellRow(neighbour)][neighbour.Col] != m.regions[row][col] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// cellType returns the merged type of the cell in the row and col of the
+// maze cells carved by partial mazes with the given indexes. Stairs are kept
+// by every strategy unless the cell becomes a wall, so floors stay connected.
+func (m *merger) cellType(row, col int, carved []int) domain.CellType {
+	tpe := m.strategyCellType(row, col, carved)
+	if tpe == domain.Wall {
+		return tpe
+	}
+
+	for _, partial := range carved {
+		if m.partials[partial].Cells[row][col] == domain.Stairs {
+			return domain.Stairs
+		}
+	}
+
+	return tpe
+}
+
+func (m *merger) strategyCellType(row, col int, carved []int) domain.CellType {
+	if len(carved) == 0 {
+		return domain.Wall
+	}
+
+	tpe := func(partial int) domain.CellType {
+		return m.partials[partial].Cells[row][col]
+	}
+
+	switch m.strategy {
+	case MergeCheapest:
+		cheapest := tpe(carved[0])
+
+		for _, partial := range carved[1:] {
+			if tpe(partial).Cost() < cheapest.Cost() {
+				cheapest = tpe(partial)
+			}
+		}
+
+		return cheapest
+
+	case MergeStartSide:
+		return tpe(carved[0])
+
+	case MergeSeam:
+		owner := m.regions[row][col]
+		if tpe(owner) != domain.Wall {
+			return tpe(owner)
+		}
+
+		if !m.onSeam(row, col) {
+			return domain.Wall
+		}
+
+	case MergeInterleave:
+		return tpe(carved[(row^col)%len(carved)])
+
+	case MergeRandom:
+	}
+
+	if len(carved) == 1 {
+		return tpe(carved[0])
+	}
+
+	return tpe(carved[m.rnd.IntN(len(carved))])
+}
+
+// mergeMazes combines partial mazes into one: a cell is a wall if no partial
+// maze carved it, conflicts are settled by the merge strategy.
+func (m *merger) mergeMazes(
+	ctx context.Context,
+	drawingChan chan<- domain.CellPaintingData,
+	processID int,
+) (domain.Maze, error) {
+	data := m.partials[0].Data
+	mergedCells := newGrid(data).newCells()
+	carved := make([]int, 0, len(m.partials))
+
+	for i := range data.Rows() {
+		for j := range data.Width {
+			carved = carved[:0]
+
+			for k, partial := range m.partials {
+				if partial.Cells[i][j] != domain.Wall {
+					carved = append(carved, k)
+				}
+			}
+
+			mergedCells[i][j] = m.cellType(i, j, carved)
+
+			err := send(ctx, drawingChan, domain.NewCellPaintingData(i, j, mergedCells[i][j], processID, mergeDelay))
+			if err != nil {
+				return domain.Maze{}, err
+			}
+		}
+	}
+
+	// stairs whose other end wasn't merged lead nowhere
+	grd := newGrid(data)
+
+	for i, row := range mergedCells {
+		for j, tpe := range row {
+			if tpe == domain.Stairs && !grd.hasStairs(mergedCells, data.CoordAt(i, j)) {
+				row[j] = domain.Passage
+
+				err := send(ctx, drawingChan, domain.NewCellPaintingData(i, j, domain.Passage, processID, mergeDelay))
+				if err != nil {
+					return domain.Maze{}, err
+				}
+			}
+		}
+	}
+
+	return domain.NewMaze(data, mergedCells), nil
+}
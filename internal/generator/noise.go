@@ -0,0 +1,57 @@
+package generator
+
+import "math"
+
+// valueNoise is a smooth pseudo-random function of a cell with values in
+// [0, 1]. Random values are placed on a lattice with the given cell size and
+// interpolated between lattice points; two octaves make the shapes less regular.
+type valueNoise struct {
+	seed  uint64
+	scale float64
+}
+
+func newValueNoise(seed uint64, scale float64) valueNoise {
+	return valueNoise{
+		seed:  seed,
+		scale: scale,
+	}
+}
+
+// lattice returns a pseudo-random value in [0, 1] for the lattice point (x, y)
+// using the splitmix64 finalizer.
+func (n valueNoise) lattice(x, y int, octave uint64) float64 {
+	h := n.seed ^ octave*0x9e3779b97f4a7c15
+	h ^= uint64(x)*0xbf58476d1ce4e5b9 + uint64(y)*0x94d049bb133111eb
+	h = (h ^ (h >> 30)) * 0xbf58476d1ce4e5b9
+	h = (h ^ (h >> 27)) * 0x94d049bb133111eb
+	h ^= h >> 31
+
+	return float64(h>>11) / float64(1<<53)
+}
+
+func smoothstep(t float64) float64 {
+	return t * t * (3 - 2*t)
+}
+
+func lerp(a, b, t float64) float64 {
+	return a + (b-a)*t
+}
+
+func (n valueNoise) octave(row, col int, scale float64, octave uint64) float64 {
+	y, x := float64(row)/scale, float64(col)/scale
+	y0, x0 := math.Floor(y), math.Floor(x)
+	ty, tx := smoothstep(y-y0), smoothstep(x-x0)
+	iy, ix := int(y0), int(x0)
+
+	top := lerp(n.lattice(ix, iy, octave), n.lattice(ix+1, iy, octave), tx)
+	bottom := lerp(n.lattice(ix, iy+1, octave), n.lattice(ix+1, iy+1, octave), tx)
+
+	return lerp(top, bottom, ty)
+}
+
+func (n valueNoise) at(row, col int) float64 {
+	const detailWeight = 0.3
+
+	return (1-detailWeight)*n.octave(row, col, n.scale, 0) +
+		detailWeight*n.octave(row, col, n.scale/2, 1)
+}
@@ -0,0 +1,81 @@
+package generator
+
+import "github.com/LLIEPJIOK/mazegenerator/internal/domain"
+
+// originCandidates is the number of random cells compared when an origin is
+// placed automatically. More candidates spread origins more evenly.
+const originCandidates = 16
+
+// origins returns coordinates the partial mazes grow from: start and end
+// first, then explicitly set origins and then automatically placed ones.
+// Algorithms which build the whole maze at once grow from the start only.
+func (g *Generator) origins(data domain.MazeData, rnd *randomSource) ([]domain.Coord, error) {
+	origins := make([]domain.Coord, 0, max(g.workers, len(g.extraOrigins)+2))
+	origins = append(origins, data.Start, data.End)
+	origins = append(origins, g.extraOrigins...)
+
+	for _, origin := range origins {
+		if !data.Contains(origin) || data.Constraints.Wall(origin) {
+			return nil, NewErrInvalidOrigin(origin)
+		}
+	}
+
+	if _, ok := g.algo.(singleOrigin); ok {
+		return origins[:1], nil
+	}
+
+	if len(origins) >= g.workers {
+		return origins, nil
+	}
+
+	allowed := allowedCells(data)
+
+	for len(origins) < g.workers {
+		origins = append(origins, placeOrigin(data, allowed, origins, rnd))
+	}
+
+	return origins, nil
+}
+
+// allowedCells lists cells origins may be placed in: cells inside the mask
+// which aren't fixed walls.
+func allowedCells(data domain.MazeData) []domain.Coord {
+	allowed := make([]domain.Coord, 0)
+
+	for level := range data.Floors() {
+		for row := range data.Height {
+			for col := range data.Topology.RowLength(row, data.Width) {
+				coord := domain.NewLevelCoord(level, row, col)
+
+				if data.Contains(coord) && !data.Constraints.Wall(coord) {
+					allowed = append(allowed, coord)
+				}
+			}
+		}
+	}
+
+	return allowed
+}
+
+// placeOrigin picks the random allowed cell farthest from already placed
+// origins. Allowed cells always include the start, so they're never empty.
+func placeOrigin(data domain.MazeData, allowed, origins []domain.Coord, rnd *randomSource) domain.Coord {
+	var best domain.Coord
+
+	bestDist := -1
+
+	for range originCandidates {
+		candidate := allowed[rnd.IntN(len(allowed))]
+		dist := data.Height + data.Width + data.Floors()
+
+		for _, origin := range origins {
+			dist = min(dist, data.Distance(candidate, origin))
+		}
+
+		if dist > bestDist {
+			best, bestDist = candidate, dist
+		}
+	}
+
+	return best
+}
@@ -1,85 +1,74 @@
 package generator
 
 import (
-	"crypto/rand"
-	"fmt"
-	"math/big"
+	"context"
 
 	"github.com/LLIEPJIOK/mazegenerator/internal/domain"
+	"github.com/LLIEPJIOK/mazegenerator/internal/registry"
 )
 
-type Prim struct {
-	dir domain.Direction
-}
+type Prim struct{}
 
 func NewPrim() *Prim {
-	return &Prim{
-		dir: domain.DefaultDirection(),
-	}
+	return &Prim{}
 }
 
 func (p *Prim) createMazeCellsFromCoord(
-	height, width int,
+	ctx context.Context,
+	grd grid,
 	start domain.Coord,
+	rnd *randomSource,
 	drawingChan chan<- cell,
 ) ([][]domain.CellType, error) {
-	cells := make([][]domain.CellType, height)
-
-	for i := range height {
-		cells[i] = make([]domain.CellType, width)
-	}
+	cells := grd.newCells()
 
-	waitList := make([]domain.Coord, 0)
+	waitList := grd.growthNeighbours(start, rnd)
 
-	for i := range len(p.dir.Rows) {
-		newRowID, newColID := start.Row+p.dir.Rows[i], start.Col+p.dir.Cols[i]
-		if min(newRowID, newColID) < 0 || newRowID >= height || newColID >= width {
-			continue
-		}
-
-		waitList = append(waitList, domain.NewCoord(newRowID, newColID))
-	}
+	grd.carve(cells, start, grd.terrain(start, rnd), drawingChan, drawingDelay)
 
-	cells[start.Row][start.Col] = domain.Passage
-	drawingChan <- newCell(start.Row, start.Col, domain.Passage, drawingDelay)
+	var buf [maxNeighbours]domain.Coord
 
 	for len(waitList) != 0 {
-		randID, err := rand.Int(rand.Reader, big.NewInt(int64(len(waitList))))
-		if err != nil {
-			return nil, fmt.Errorf("generate random processID: %w", err)
+		if err := ctx.Err(); err != nil {
+			return nil, err
 		}
 
-		randCoord := waitList[randID.Int64()]
-		waitList[randID.Int64()], waitList[len(waitList)-1] = waitList[len(waitList)-1], waitList[randID.Int64()]
+		randID := rnd.IntN(len(waitList))
+
+		randCoord := waitList[randID]
+		waitList[randID], waitList[len(waitList)-1] = waitList[len(waitList)-1], waitList[randID]
 		waitList = waitList[:len(waitList)-1]
 
-		cntWalls, cntBorders := 0, 0
+		cntWalls, cntPassages := 0, 0
 
-		for i := range p.dir.Rows {
-			newRowID, newColID := randCoord.Row+p.dir.Rows[i], randCoord.Col+p.dir.Cols[i]
-			if min(newRowID, newColID) < 0 || newRowID >= height || newColID >= width {
-				cntBorders++
+		for _, neighbour := range grd.appendNeighbours(buf[:0], randCoord) {
+			if grd.cell(cells, neighbour) != domain.Wall {
+				cntPassages++
 				continue
 			}
 
-			if cells[newRowID][newColID] == domain.Wall {
-				waitList = append(waitList, domain.NewCoord(newRowID, newColID))
-				cntWalls++
+			if !grd.grows(randCoord, neighbour, rnd) {
+				continue
 			}
+
+			waitList = append(waitList, neighbour)
+			cntWalls++
 		}
 
-		if cntWalls+cntBorders < 3 {
+		// a cell joining two passages would make a loop, only cells fixed
+		// as passages are carved anyway
+		if cntPassages > 1 && !grd.constraints.Open(randCoord) {
 			waitList = waitList[:len(waitList)-cntWalls]
 		} else {
-			tpe, err := randomCellType()
-			if err != nil {
-				return nil, fmt.Errorf("getting random cell type: %w", err)
-			}
-
-			cells[randCoord.Row][randCoord.Col] = tpe
-			drawingChan <- newCell(randCoord.Row, randCoord.Col, tpe, drawingDelay)
+			grd.carve(cells, randCoord, grd.terrain(randCoord, rnd), drawingChan, drawingDelay)
 		}
 	}
 
 	return cells, nil
 }
+
+func primEntry() registry.Entry[Algorithm] {
+	return plainEntry("prim", "Prim's algorithm, short corridors branching everywhere", nil, func() Algorithm {
+		return NewPrim()
+	})
+}
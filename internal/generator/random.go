@@ -0,0 +1,67 @@
+package generator
+
+import (
+	"math/rand/v2"
+
+	"github.com/LLIEPJIOK/mazegenerator/internal/domain"
+)
+
+// randomSource is a seeded random number generator which also picks types of
+// carved cells according to the terrain profile.
+type randomSource struct {
+	*rand.Rand
+	terrain domain.TerrainProfile
+}
+
+func newRandomSource(seed, stream uint64, terrain domain.TerrainProfile) *randomSource {
+	return &randomSource{
+		Rand:    rand.New(rand.NewPCG(seed, stream)),
+		terrain: terrain,
+	}
+}
+
+func (r *randomSource) cellType() domain.CellType {
+	total := r.terrain.Total()
+	if total == 0 {
+		return domain.Passage
+	}
+
+	numb := r.IntN(total)
+
+	for _, tpe := range domain.TerrainTypes() {
+		weight := max(r.terrain[tpe], 0)
+		if numb < weight {
+			return tpe
+		}
+
+		numb -= weight
+	}
+
+	return domain.Passage
+}
+
+// themeType picks a type other than a plain passage according to the terrain
+// profile, so themed areas stand out. Profiles without such types give passages.
+func (r *randomSource) themeType() domain.CellType {
+	total := r.terrain.Total() - max(r.terrain[domain.Passage], 0)
+	if total == 0 {
+		return domain.Passage
+	}
+
+	numb := r.IntN(total)
+
+	for _, tpe := range domain.TerrainTypes() {
+		if tpe == domain.Passage {
+			continue
+		}
+
+		weight := max(r.terrain[tpe], 0)
+		if numb < weight {
+			return tpe
+		}
+
+		numb -= weight
+	}
+
+	return domain.Passage
+}
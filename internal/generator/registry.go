@@ -0,0 +1,63 @@
+package generator
+
+import (
+	"github.com/LLIEPJIOK/mazegenerator/internal/domain"
+	"github.com/LLIEPJIOK/mazegenerator/internal/registry"
+)
+
+// Grids the algorithms can handle, they match the checks the algorithms
+// make before carving.
+
+func supportsLattice(data domain.MazeData) bool {
+	return newGrid(data).requireLattice() == nil
+}
+
+func supportsBorderedLattice(data domain.MazeData) bool {
+	grd := newGrid(data)
+
+	return grd.requireBorders() == nil && grd.requireLattice() == nil
+}
+
+func supportsSquareFloor(data domain.MazeData) bool {
+	return newGrid(data).requireSquareFloor() == nil
+}
+
+// NewRegistry returns all generation algorithms in the order they are listed in.
+// Every algorithm declares its entry next to its implementation, the list
+// only sets the order of the menus.
+func NewRegistry() *registry.Registry[Algorithm] {
+	reg := registry.New[Algorithm]()
+
+	for _, entry := range []registry.Entry[Algorithm]{
+		primEntry(),
+		backtrackEntry(),
+		kruskalEntry(),
+		wilsonEntry(),
+		ellerEntry(),
+		divisionEntry(),
+		huntAndKillEntry(),
+		aldousBroderEntry(),
+		growingTreeEntry(),
+		dungeonEntry(),
+		caveEntry(),
+	} {
+		// names above are unique, so registering never fails
+		_ = reg.Register(entry)
+	}
+
+	return reg
+}
+
+// plainEntry returns the entry of an algorithm without parameters.
+func plainEntry(
+	name, description string,
+	supports func(domain.MazeData) bool,
+	newAlgorithm func() Algorithm,
+) registry.Entry[Algorithm] {
+	return registry.Entry[Algorithm]{
+		Name:        name,
+		Description: description,
+		Supports:    supports,
+		New:         func(registry.Values) Algorithm { return newAlgorithm() },
+	}
+}
@@ -0,0 +1,185 @@
+package generator
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/LLIEPJIOK/mazegenerator/internal/domain"
+)
+
+const repairDelay = 20 * time.Millisecond
+
+// repair opens the fewest walls needed to reach the end of the maze from the
+// start and returns the opened cells. Walls fixed by constraints are never
+// opened. Cells on adjacent floors the way goes through become stairs.
+func repair(
+	ctx context.Context,
+	maze domain.Maze,
+	rnd *randomSource,
+	drawingChan chan<- domain.CellPaintingData,
+	processID int,
+) ([]domain.Coord, error) {
+	data := maze.Data
+	dist, prev := repairDistances(maze)
+
+	switch dist[data.CellRow(data.End)][data.End.Col] {
+	case 0:
+		return nil, nil
+	case math.MaxInt:
+		if !data.Connected(data.Start, data.End) {
+			return nil, ErrDisconnectedMask{}
+		}
+
+		return nil, ErrWalledOff{}
+	}
+
+	r := &repairer{
+		ctx:         ctx,
+		maze:        maze,
+		rnd:         rnd,
+		drawingChan: drawingChan,
+		processID:   processID,
+		opened:      make([]domain.Coord, 0, dist[data.CellRow(data.End)][data.End.Col]),
+	}
+
+	if err := r.walk(prev); err != nil {
+		return nil, err
+	}
+
+	return r.opened, nil
+}
+
+// repairDistances returns the number of walls to open on the way from the
+// start to every cell and the previous cell of each way. Entering a cell
+// which isn't passable costs 1 and entering a passable one costs 0, so a 0-1
+// BFS from the start finds the cheapest ways through the walls. Cells which
+// can't be reached have the distance math.MaxInt.
+func repairDistances(maze domain.Maze) ([][]int, [][]domain.Coord) {
+	data := maze.Data
+
+	cost := func(from, to domain.Coord) int {
+		if maze.Passable(from, to) {
+			return 0
+		}
+
+		return 1
+	}
+
+	dist := newFlatCells[int](data.Rows(), data.Width)
+	prev := newFlatCells[domain.Coord](data.Rows(), data.Width)
+
+	for _, row := range dist {
+		for j := range row {
+			row[j] = math.MaxInt
+		}
+	}
+
+	distance := func(c domain.Coord) *int {
+		return &dist[data.CellRow(c)][c.Col]
+	}
+
+	*distance(data.Start) = cost(data.Start, data.Start)
+	level, cur := *distance(data.Start), []domain.Coord{data.Start}
+
+	var buf [maxNeighbours]domain.Coord
+
+	for len(cur) != 0 && *distance(data.End) > level {
+		var next []domain.Coord
+
+		// cells reached without opening walls join the current level
+		for k := 0; k < len(cur); k++ {
+			c := cur[k]
+			if *distance(c) != level {
+				continue
+			}
+
+			for _, n := range data.AppendNeighbours(buf[:0], c) {
+				if data.Constraints.Wall(n) {
+					continue
+				}
+
+				cost := cost(c, n)
+				if level+cost >= *distance(n) {
+					continue
+				}
+
+				*distance(n) = level + cost
+				prev[data.CellRow(n)][n.Col] = c
+
+				if cost == 0 {
+					cur = append(cur, n)
+				} else {
+					next = append(next, n)
+				}
+			}
+		}
+
+		cur = next
+		level++
+	}
+
+	return dist, prev
+}
+
+// repairer opens the walls on the way found by repairDistances and draws
+// them.
+type repairer struct {
+	ctx         context.Context
+	maze        domain.Maze
+	rnd         *randomSource
+	drawingChan chan<- domain.CellPaintingData
+	processID   int
+	opened      []domain.Coord
+}
+
+// walk opens the walls on the way from the end back to the start and turns
+// cells where the way changes floors into stairs.
+func (r *repairer) walk(prev [][]domain.Coord) error {
+	data := r.maze.Data
+
+	for c := data.End; ; c = prev[data.CellRow(c)][c.Col] {
+		if err := r.open(c); err != nil {
+			return err
+		}
+
+		if c == data.Start {
+			return nil
+		}
+
+		p := prev[data.CellRow(c)][c.Col]
+		if p.Level == c.Level {
+			continue
+		}
+
+		if err := r.open(p); err != nil {
+			return err
+		}
+
+		if err := r.set(c, domain.Stairs); err != nil {
+			return err
+		}
+
+		if err := r.set(p, domain.Stairs); err != nil {
+			return err
+		}
+	}
+}
+
+// open carves the cell with random terrain if it's a wall.
+func (r *repairer) open(c domain.Coord) error {
+	if r.maze.Cell(c) != domain.Wall {
+		return nil
+	}
+
+	r.opened = append(r.opened, c)
+
+	return r.set(c, r.rnd.cellType())
+}
+
+func (r *repairer) set(c domain.Coord, tpe domain.CellType) error {
+	row := r.maze.Data.CellRow(c)
+	r.maze.Cells[row][c.Col] = tpe
+
+	return send(r.ctx, r.drawingChan, domain.NewCellPaintingData(row, c.Col, tpe, r.processID, repairDelay))
+}
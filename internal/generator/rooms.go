@@ -0,0 +1,49 @@
+package generator
+
+import (
+	"slices"
+
+	"github.com/LLIEPJIOK/mazegenerator/internal/domain"
+)
+
+// Helpers for algorithms which carve rooms lying on the same lattice as the
+// start cell and walls lying between them.
+
+func (g grid) rooms(start domain.Coord) []domain.Coord {
+	res := make([]domain.Coord, 0)
+
+	for i := range g.height {
+		for j := range g.width {
+			if room := domain.NewCoord(i, j); g.topology.SameLattice(room, start) {
+				res = append(res, room)
+			}
+		}
+	}
+
+	return res
+}
+
+func (g grid) roomNeighbours(room domain.Coord) []domain.Coord {
+	return g.appendRoomNeighbours(make([]domain.Coord, 0, g.topology.Directions()), room)
+}
+
+// appendRoomNeighbours appends rooms next to the room to dst, so loops can
+// reuse a buffer instead of allocating neighbours of every room.
+func (g grid) appendRoomNeighbours(dst []domain.Coord, room domain.Coord) []domain.Coord {
+	from := len(dst)
+
+	for i := range g.topology.Directions() {
+		next := g.topology.Wrap(g.topology.Step(room, i, 2), g.height, g.width)
+
+		// narrow tori reach the same room both ways
+		if g.inside(next) && next != room && !slices.Contains(dst[from:], next) {
+			dst = append(dst, next)
+		}
+	}
+
+	return dst
+}
+
+func (g grid) between(first, second domain.Coord) domain.Coord {
+	return g.topology.Between(first, second, g.height, g.width)
+}
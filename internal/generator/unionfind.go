@@ -0,0 +1,47 @@
+package generator
+
+type unionFind struct {
+	parent []int
+	size   []int
+}
+
+func newUnionFind(n int) *unionFind {
+	parent := make([]int, n)
+	size := make([]int, n)
+
+	for i := range n {
+		parent[i] = i
+		size[i] = 1
+	}
+
+	return &unionFind{
+		parent: parent,
+		size:   size,
+	}
+}
+
+func (uf *unionFind) find(x int) int {
+	for uf.parent[x] != x {
+		uf.parent[x] = uf.parent[uf.parent[x]]
+		x = uf.parent[x]
+	}
+
+	return x
+}
+
+// union joins sets of x and y and reports whether they were different.
+func (uf *unionFind) union(x, y int) bool {
+	rootX, rootY := uf.find(x), uf.find(y)
+	if rootX == rootY {
+		return false
+	}
+
+	if uf.size[rootX] < uf.size[rootY] {
+		rootX, rootY = rootY, rootX
+	}
+
+	uf.parent[rootY] = rootX
+	uf.size[rootX] += uf.size[rootY]
+
+	return true
+}
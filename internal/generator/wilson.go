@@ -0,0 +1,135 @@
+package generator
+
+import (
+	"context"
+
+	"github.com/LLIEPJIOK/mazegenerator/internal/domain"
+	"github.com/LLIEPJIOK/mazegenerator/internal/registry"
+)
+
+// Wilson builds a uniform spanning tree over the same rooms as Kruskal using
+// loop-erased random walks. Walks are drawn as passages and erased loops are
+// drawn back as walls. The tree is built once from the start, because the
+// union of several trees is neither a tree nor uniform.
+type Wilson struct{}
+
+func NewWilson() *Wilson {
+	return &Wilson{}
+}
+
+func (w *Wilson) singleOrigin() {}
+
+// walk returns the loop-erased random walk from the room to the maze.
+// pathIDs holds the index in the walk plus one of every room on it and is
+// cleared before returning, so it's shared by all walks. A walk can take
+// long on big mazes, so it stops as soon as ctx is done.
+func (w *Wilson) walk(
+	ctx context.Context,
+	grd grid,
+	from domain.Coord,
+	cells [][]domain.CellType,
+	pathIDs [][]int,
+	rnd *randomSource,
+	drawingChan chan<- cell,
+) ([]domain.Coord, error) {
+	path := []domain.Coord{from}
+	pathIDs[from.Row][from.Col] = 1
+
+	draw(drawingChan, newCell(from.Row, from.Col, domain.Passage, drawingDelay))
+
+	var buf [maxNeighbours]domain.Coord
+
+	for cur := from; cells[cur.Row][cur.Col] == domain.Wall; {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		neighbours := grd.appendRoomNeighbours(buf[:0], cur)
+		next := neighbours[rnd.IntN(len(neighbours))]
+
+		if id := pathIDs[next.Row][next.Col] - 1; id >= 0 {
+			// erase the loop and the wall leading to it
+			for k := len(path) - 1; k > id; k-- {
+				wall := grd.between(path[k-1], path[k])
+				draw(drawingChan, newCell(path[k].Row, path[k].Col, domain.Wall, drawingDelay))
+				draw(drawingChan, newCell(wall.Row, wall.Col, domain.Wall, drawingDelay))
+
+				pathIDs[path[k].Row][path[k].Col] = 0
+			}
+
+			path = path[:id+1]
+			cur = next
+
+			continue
+		}
+
+		wall := grd.between(cur, next)
+		draw(drawingChan, newCell(wall.Row, wall.Col, domain.Passage, drawingDelay))
+
+		if cells[next.Row][next.Col] == domain.Wall {
+			draw(drawingChan, newCell(next.Row, next.Col, domain.Passage, drawingDelay))
+		}
+
+		pathIDs[next.Row][next.Col] = len(path) + 1
+		path = append(path, next)
+		cur = next
+	}
+
+	for _, room := range path {
+		pathIDs[room.Row][room.Col] = 0
+	}
+
+	return path, nil
+}
+
+func (w *Wilson) createMazeCellsFromCoord(
+	ctx context.Context,
+	grd grid,
+	start domain.Coord,
+	rnd *randomSource,
+	drawingChan chan<- cell,
+) ([][]domain.CellType, error) {
+	if err := grd.requireLattice(); err != nil {
+		return nil, err
+	}
+
+	cells := grd.newCells()
+
+	carve := func(coord domain.Coord) {
+		tpe := rnd.cellType()
+		cells[coord.Row][coord.Col] = tpe
+		draw(drawingChan, newCell(coord.Row, coord.Col, tpe, drawingDelay))
+	}
+
+	carve(start)
+
+	pathIDs := newFlatCells[int](grd.height, grd.width)
+	order := grd.rooms(start)
+	rnd.Shuffle(len(order), func(i, j int) {
+		order[i], order[j] = order[j], order[i]
+	})
+
+	for _, room := range order {
+		if cells[room.Row][room.Col] != domain.Wall {
+			continue
+		}
+
+		path, err := w.walk(ctx, grd, room, cells, pathIDs, rnd, drawingChan)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range len(path) - 1 {
+			carve(path[i])
+			carve(grd.between(path[i], path[i+1]))
+		}
+	}
+
+	return cells, nil
+}
+
+func wilsonEntry() registry.Entry[Algorithm] {
+	return plainEntry("wilson", "Wilson's algorithm, samples all mazes uniformly", supportsLattice, func() Algorithm {
+		return NewWilson()
+	})
+}
@@ -0,0 +1,7 @@
+package mask
+
+type ErrEmptyMask struct{}
+
+func (e ErrEmptyMask) Error() string {
+	return "mask has no cells to carve"
+}
@@ -0,0 +1,135 @@
+// Package mask reads stencils which give mazes their shape.
+package mask
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/LLIEPJIOK/mazegenerator/internal/domain"
+)
+
+// threshold is the maximum brightness of a pixel marking a cell which may be
+// carved, from 0 for black to 0xffff for white.
+const threshold = 0x8000
+
+// FromText reads a text mask: every line is a row of cells, '.' and spaces
+// mark cells outside the mask and any other character marks a cell which may
+// be carved.
+func FromText(r io.Reader) (domain.Mask, error) {
+	var mask domain.Mask
+
+	scan := bufio.NewScanner(r)
+
+	for scan.Scan() {
+		line := []rune(strings.TrimRight(scan.Text(), "\r"))
+		row := make([]bool, len(line))
+
+		for i, ch := range line {
+			row[i] = ch != '.' && ch != ' '
+		}
+
+		mask = append(mask, row)
+	}
+
+	if err := scan.Err(); err != nil {
+		return nil, fmt.Errorf("read lines: %w", err)
+	}
+
+	return normalize(mask)
+}
+
+// FromPNG reads an image mask: every pixel is a cell, dark opaque pixels mark
+// cells which may be carved and light or transparent ones mark cells outside
+// the mask.
+func FromPNG(r io.Reader) (domain.Mask, error) {
+	img, err := png.Decode(r)
+	if err != nil {
+		return nil, fmt.Errorf("decode png: %w", err)
+	}
+
+	return fromImage(img)
+}
+
+func fromImage(img image.Image) (domain.Mask, error) {
+	bounds := img.Bounds()
+	mask := make(domain.Mask, bounds.Dy())
+
+	for y := range bounds.Dy() {
+		mask[y] = make([]bool, bounds.Dx())
+
+		for x := range bounds.Dx() {
+			px := img.At(bounds.Min.X+x, bounds.Min.Y+y)
+			_, _, _, alpha := px.RGBA()
+			gray := color.Gray16Model.Convert(px).(color.Gray16)
+
+			mask[y][x] = alpha >= threshold && gray.Y < threshold
+		}
+	}
+
+	return normalize(mask)
+}
+
+// normalize trims rows and columns without allowed cells around the mask and
+// pads its rows to the same width.
+func normalize(mask domain.Mask) (domain.Mask, error) {
+	top, bottom, left, right := len(mask), -1, mask.Width(), -1
+
+	for i, row := range mask {
+		for j, allowed := range row {
+			if allowed {
+				top, bottom = min(top, i), max(bottom, i)
+				left, right = min(left, j), max(right, j)
+			}
+		}
+	}
+
+	if bottom < 0 {
+		return nil, ErrEmptyMask{}
+	}
+
+	res := make(domain.Mask, bottom-top+1)
+
+	for i := range res {
+		res[i] = make([]bool, right-left+1)
+
+		for j := range res[i] {
+			res[i][j] = mask.Allows(domain.NewCoord(top+i, left+j))
+		}
+	}
+
+	return res, nil
+}
+
+// Load reads the mask from the file: PNG images by the .png extension and
+// text masks otherwise.
+func Load(path string) (mask domain.Mask, err error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open file: %w", err)
+	}
+
+	defer func() {
+		if closeErr := file.Close(); closeErr != nil && err == nil {
+			err = fmt.Errorf("close file: %w", closeErr)
+		}
+	}()
+
+	if strings.EqualFold(filepath.Ext(path), ".png") {
+		mask, err = FromPNG(file)
+	} else {
+		mask, err = FromText(file)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("read mask %s: %w", path, err)
+	}
+
+	return mask, nil
+}
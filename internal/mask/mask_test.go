@@ -0,0 +1,165 @@
+package mask_test
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/LLIEPJIOK/mazegenerator/internal/domain"
+	"github.com/LLIEPJIOK/mazegenerator/internal/mask"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromText(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		text     string
+		expected domain.Mask
+	}{
+		{
+			text: "###\n#.#\n###",
+			expected: domain.Mask{
+				{true, true, true},
+				{true, false, true},
+				{true, true, true},
+			},
+		},
+		{
+			text: "....\n.XX.\n.X\n....\n",
+			expected: domain.Mask{
+				{true, true},
+				{true, false},
+			},
+		},
+		{
+			text: "  *\r\n * *\r\n",
+			expected: domain.Mask{
+				{false, true, false},
+				{true, false, true},
+			},
+		},
+	}
+
+	for i, testCase := range testCases {
+		t.Run(fmt.Sprintf("#%d", i+1), func(t *testing.T) {
+			t.Parallel()
+
+			got, err := mask.FromText(strings.NewReader(testCase.text))
+			require.NoError(t, err, "mask should be read without error")
+			require.Equal(t, testCase.expected, got, "masks should be equal")
+		})
+	}
+}
+
+func encodePNG(t *testing.T, rows ...string) []byte {
+	t.Helper()
+
+	img := image.NewNRGBA(image.Rect(0, 0, len(rows[0]), len(rows)))
+
+	for y, row := range rows {
+		for x, ch := range row {
+			switch ch {
+			case '#':
+				img.Set(x, y, color.Black)
+			case '.':
+				img.Set(x, y, color.White)
+			default:
+				img.Set(x, y, color.Transparent)
+			}
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, png.Encode(buf, img), "image should be encoded")
+
+	return buf.Bytes()
+}
+
+func TestFromPNG(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		rows     []string
+		expected domain.Mask
+	}{
+		{
+			rows: []string{"##.", "#..", "###"},
+			expected: domain.Mask{
+				{true, true, false},
+				{true, false, false},
+				{true, true, true},
+			},
+		},
+		{
+			rows: []string{"     ", " #.# ", " ### ", "     "},
+			expected: domain.Mask{
+				{true, false, true},
+				{true, true, true},
+			},
+		},
+	}
+
+	for i, testCase := range testCases {
+		t.Run(fmt.Sprintf("#%d", i+1), func(t *testing.T) {
+			t.Parallel()
+
+			got, err := mask.FromPNG(bytes.NewReader(encodePNG(t, testCase.rows...)))
+			require.NoError(t, err, "mask should be read without error")
+			require.Equal(t, testCase.expected, got, "masks should be equal")
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	textPath, pngPath := filepath.Join(dir, "mask.txt"), filepath.Join(dir, "mask.PNG")
+
+	require.NoError(t, os.WriteFile(textPath, []byte("#.\n##\n"), 0o600))
+	require.NoError(t, os.WriteFile(pngPath, encodePNG(t, "#.", "##"), 0o600))
+
+	expected := domain.Mask{
+		{true, false},
+		{true, true},
+	}
+
+	for i, path := range []string{textPath, pngPath} {
+		t.Run(fmt.Sprintf("#%d", i+1), func(t *testing.T) {
+			t.Parallel()
+
+			got, err := mask.Load(path)
+			require.NoError(t, err, "mask should be loaded without error")
+			require.Equal(t, expected, got, "masks should be equal")
+		})
+	}
+}
+
+func TestLoadWithError(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	emptyPath, brokenPath := filepath.Join(dir, "empty.txt"), filepath.Join(dir, "broken.png")
+
+	require.NoError(t, os.WriteFile(emptyPath, []byte("...\n. .\n"), 0o600))
+	require.NoError(t, os.WriteFile(brokenPath, []byte("###"), 0o600))
+
+	for i, path := range []string{emptyPath, brokenPath, filepath.Join(dir, "missing.txt")} {
+		t.Run(fmt.Sprintf("#%d", i+1), func(t *testing.T) {
+			t.Parallel()
+
+			_, err := mask.Load(path)
+			require.Error(t, err, "mask shouldn't be loaded")
+		})
+	}
+
+	_, err := mask.Load(emptyPath)
+	require.ErrorAs(t, err, &mask.ErrEmptyMask{}, "mask without cells should be rejected")
+}
@@ -6,7 +6,7 @@ type PaintingMaze struct {
 	cells [][]map[int]domain.CellType
 }
 
-func newPaintingMaze(height, width int) PaintingMaze {
+func NewPaintingMaze(height, width int) PaintingMaze {
 	cells := make([][]map[int]domain.CellType, height)
 
 	for i := range height {
@@ -34,14 +34,35 @@ func (dm *PaintingMaze) GetCellType(x, y int) domain.CellType {
 	return domain.Ambiguous
 }
 
+// GetSenderID returns the worker which is the only one to have carved the
+// cell. It returns 0 for walls, ambiguous and merged cells.
+func (dm *PaintingMaze) GetSenderID(x, y int) int {
+	if len(dm.cells[x][y]) != 1 {
+		return 0
+	}
+
+	for senderID := range dm.cells[x][y] {
+		return senderID
+	}
+
+	return 0
+}
+
+// AddCellType records the cell of a worker. Cells sent with SenderID 0 come
+// from merging and replace what all workers have carved there.
 func (dm *PaintingMaze) AddCellType(cellData domain.CellPaintingData) {
-	if cellData.Tpe == domain.Wall {
-		delete(dm.cells[cellData.Row][cellData.Col], cellData.SenderID)
-	} else {
-		if cellData.SenderID == 0 {
-			clear(dm.cells[cellData.Row][cellData.Col])
-		}
+	cell := dm.cells[cellData.Row][cellData.Col]
 
-		dm.cells[cellData.Row][cellData.Col][cellData.SenderID] = cellData.Tpe
+	switch {
+	case cellData.SenderID == 0:
+		clear(cell)
+
+		if cellData.Tpe != domain.Wall {
+			cell[0] = cellData.Tpe
+		}
+	case cellData.Tpe == domain.Wall:
+		delete(cell, cellData.SenderID)
+	default:
+		cell[cellData.SenderID] = cellData.Tpe
 	}
 }
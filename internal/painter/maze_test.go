@@ -0,0 +1,77 @@
+package painter_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/LLIEPJIOK/mazegenerator/internal/domain"
+	"github.com/LLIEPJIOK/mazegenerator/internal/painter"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddCellType(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		cells            []domain.CellPaintingData
+		expectedType     domain.CellType
+		expectedSenderID int
+	}{
+		{
+			cells: []domain.CellPaintingData{
+				domain.NewCellPaintingData(0, 0, domain.Passage, 1, 0),
+			},
+			expectedType:     domain.Passage,
+			expectedSenderID: 1,
+		},
+		{
+			cells: []domain.CellPaintingData{
+				domain.NewCellPaintingData(0, 0, domain.Passage, 1, 0),
+				domain.NewCellPaintingData(0, 0, domain.Sand, 2, 0),
+			},
+			expectedType:     domain.Ambiguous,
+			expectedSenderID: 0,
+		},
+		{
+			cells: []domain.CellPaintingData{
+				domain.NewCellPaintingData(0, 0, domain.Passage, 1, 0),
+				domain.NewCellPaintingData(0, 0, domain.Sand, 2, 0),
+				domain.NewCellPaintingData(0, 0, domain.Wall, 2, 0),
+			},
+			expectedType:     domain.Passage,
+			expectedSenderID: 1,
+		},
+		{
+			cells: []domain.CellPaintingData{
+				domain.NewCellPaintingData(0, 0, domain.Passage, 1, 0),
+				domain.NewCellPaintingData(0, 0, domain.Sand, 2, 0),
+				domain.NewCellPaintingData(0, 0, domain.Money, 0, 0),
+			},
+			expectedType:     domain.Money,
+			expectedSenderID: 0,
+		},
+		{
+			cells: []domain.CellPaintingData{
+				domain.NewCellPaintingData(0, 0, domain.Passage, 1, 0),
+				domain.NewCellPaintingData(0, 0, domain.Sand, 2, 0),
+				domain.NewCellPaintingData(0, 0, domain.Wall, 0, 0),
+			},
+			expectedType:     domain.Wall,
+			expectedSenderID: 0,
+		},
+	}
+
+	for i, testCase := range testCases {
+		t.Run(fmt.Sprintf("#%d", i+1), func(t *testing.T) {
+			t.Parallel()
+
+			maze := painter.NewPaintingMaze(1, 1)
+			for _, cellData := range testCase.cells {
+				maze.AddCellType(cellData)
+			}
+
+			require.Equal(t, testCase.expectedType, maze.GetCellType(0, 0), "cell types should be equal")
+			require.Equal(t, testCase.expectedSenderID, maze.GetSenderID(0, 0), "sender ids should be equal")
+		})
+	}
+}
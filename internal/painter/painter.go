@@ -9,17 +9,26 @@ import (
 	"github.com/LLIEPJIOK/mazegenerator/internal/domain"
 )
 
+// workerColours are ANSI 256-colour backgrounds of cells carved by a single
+// generation worker. Workers reuse colours when there are more of them.
+var workerColours = []int{153, 223, 194, 218, 187, 159, 217, 183}
+
+// Painter shows one floor of the maze at a time, starting from the floor of
+// the start point.
 type Painter struct {
 	out       io.Writer
 	data      domain.MazeData
 	paintMaze PaintingMaze
+	floor     int
+	path      []domain.Coord
 }
 
 func New(out io.Writer, data domain.MazeData) *Painter {
 	return &Painter{
 		out:       out,
 		data:      data,
-		paintMaze: newPaintingMaze(data.Height, data.Width),
+		paintMaze: NewPaintingMaze(data.Rows(), data.Width),
+		floor:     data.Start.Level,
 	}
 }
 
@@ -28,7 +37,14 @@ func clearScreen() {
 }
 
 func (p *Painter) moveCursor(row, col int) {
-	fmt.Fprintf(p.out, "\033[%d;%dH", row+2, 2*(col+1)+1)
+	shift := 0
+
+	// odd rows of a hex grid are shifted half a cell to the right
+	if p.data.Topology == domain.Hex {
+		shift = row & 1
+	}
+
+	fmt.Fprintf(p.out, "\033[%d;%dH", row+2, 2*(col+1)+1+shift)
 }
 
 func (p *Painter) paintStartEndString(row, col int, str string) {
@@ -38,33 +54,72 @@ func (p *Painter) paintStartEndString(row, col int, str string) {
 	fmt.Fprintf(p.out, "\033[31m%s\033[0m", str)
 }
 
+// paintWrapMarkers marks the borders of a grid whose edges wrap: cells
+// continue on the opposite side.
+func (p *Painter) paintWrapMarkers() {
+	if !p.data.Topology.Wraps() {
+		return
+	}
+
+	for col := range p.data.Width {
+		p.paintWrapMarker(-1, col, "::")
+		p.paintWrapMarker(p.data.Height, col, "::")
+	}
+
+	for row := range p.data.Height {
+		p.paintWrapMarker(row, -1, ":")
+		p.paintWrapMarker(row, p.data.Width, ":")
+	}
+}
+
+func (p *Painter) paintWrapMarker(row, col int, str string) {
+	p.moveCursor(row, col)
+
+	// ANSI code for gray letters
+	fmt.Fprintf(p.out, "\033[90m%s\033[0m", str)
+}
+
+// outside reports whether the cell next to the coord in the given direction
+// lies outside the maze: beyond its borders or its mask.
+func (p *Painter) outside(c domain.Coord, dRow, dCol int) bool {
+	return !p.data.Contains(domain.NewLevelCoord(c.Level, c.Row+dRow, c.Col+dCol))
+}
+
 func (p *Painter) paintStartEnd() {
+	p.paintWrapMarkers()
+
+	start, end := p.data.Start, p.data.End
+
 	switch {
-	case p.data.Start.Row == 0:
-		p.paintStartEndString(-1, p.data.Start.Col, "vv")
+	case start.Level != p.floor:
 
-	case p.data.Start.Row == p.data.Height-1:
-		p.paintStartEndString(p.data.Height, p.data.Start.Col, "^^")
+	case p.outside(start, -1, 0):
+		p.paintStartEndString(start.Row-1, start.Col, "vv")
 
-	case p.data.Start.Col == 0:
-		p.paintStartEndString(p.data.Start.Row, -1, ">")
+	case p.outside(start, 1, 0):
+		p.paintStartEndString(start.Row+1, start.Col, "^^")
 
-	case p.data.Start.Col == p.data.Width-1:
-		p.paintStartEndString(p.data.Start.Row, p.data.Width, "<")
+	case p.outside(start, 0, -1):
+		p.paintStartEndString(start.Row, start.Col-1, ">")
+
+	case p.outside(start, 0, 1):
+		p.paintStartEndString(start.Row, start.Col+1, "<")
 	}
 
 	switch {
-	case p.data.End.Row == 0:
-		p.paintStartEndString(-1, p.data.End.Col, "^^")
+	case end.Level != p.floor:
+
+	case p.outside(end, -1, 0):
+		p.paintStartEndString(end.Row-1, end.Col, "^^")
 
-	case p.data.End.Row == p.data.Height-1:
-		p.paintStartEndString(p.data.Height, p.data.End.Col, "vv")
+	case p.outside(end, 1, 0):
+		p.paintStartEndString(end.Row+1, end.Col, "vv")
 
-	case p.data.End.Col == 0:
-		p.paintStartEndString(p.data.End.Row, -1, "<")
+	case p.outside(end, 0, -1):
+		p.paintStartEndString(end.Row, end.Col-1, "<")
 
-	case p.data.End.Col == p.data.Width-1:
-		p.paintStartEndString(p.data.End.Row, p.data.Width, ">")
+	case p.outside(end, 0, 1):
+		p.paintStartEndString(end.Row, end.Col+1, ">")
 	}
 }
 
@@ -73,6 +128,49 @@ func (p *Painter) paint(row, col int, cellType domain.CellType) {
 	fmt.Fprint(p.out, cellType)
 }
 
+// paintCell paints the cell in the row and col of the maze cells if it lies
+// on the shown floor.
+func (p *Painter) paintCell(row, col int) {
+	// cells outside the mask stay blank, so the maze keeps its shape
+	if row/p.data.Height != p.floor || !p.data.Contains(p.data.CoordAt(row, col)) {
+		return
+	}
+
+	cellType := p.paintMaze.GetCellType(row, col)
+
+	if senderID := p.paintMaze.GetSenderID(row, col); senderID != 0 {
+		p.paintWorkerCell(row%p.data.Height, col, cellType, senderID)
+	} else {
+		p.paint(row%p.data.Height, col, cellType)
+	}
+}
+
+func cellSymbol(cellType domain.CellType) string {
+	switch cellType {
+	case domain.Money:
+		return "$$"
+	case domain.Sand:
+		return "▒▒"
+	case domain.River:
+		return "~~"
+	case domain.Stairs:
+		return "≡≡"
+	case domain.Wall, domain.Passage, domain.Ambiguous, domain.Path:
+		return "  "
+	}
+
+	return "  "
+}
+
+func (p *Painter) paintWorkerCell(row, col int, cellType domain.CellType, senderID int) {
+	p.moveCursor(row, col)
+
+	colour := workerColours[(senderID-1)%len(workerColours)]
+
+	// ANSI code for 256-colour background and black symbols
+	fmt.Fprintf(p.out, "\033[48;5;%dm\033[30m%s\033[0m", colour, cellSymbol(cellType))
+}
+
 func (p *Painter) PaintGeneration(
 	ctx context.Context,
 	cellChan <-chan domain.CellPaintingData,
@@ -90,38 +188,64 @@ func (p *Painter) PaintGeneration(
 			}
 
 			p.paintMaze.AddCellType(cellData)
-			p.paint(
-				cellData.Row,
-				cellData.Col,
-				p.paintMaze.GetCellType(cellData.Row, cellData.Col),
-			)
-			time.Sleep(cellData.Delay)
+			p.paintCell(cellData.Row, cellData.Col)
+
+			if cellData.Row/p.data.Height == p.floor {
+				time.Sleep(cellData.Delay)
+			}
 		case <-ctx.Done():
 			return
 		}
 	}
 }
 
+func (p *Painter) paintPath(path []domain.Coord, cellType func(c domain.Coord) domain.CellType) {
+	for _, v := range path {
+		if v.Level == p.floor {
+			p.paint(v.Row, v.Col, cellType(v))
+		}
+	}
+}
+
 func (p *Painter) PaintPath(pathChan <-chan []domain.Coord, delay time.Duration) {
 	defer p.moveCursor(p.data.Height+1, -1)
 
-	var prevPath []domain.Coord
-
 	for path := range pathChan {
-		for _, v := range prevPath {
-			p.paint(
-				v.Row,
-				v.Col,
-				p.paintMaze.GetCellType(v.Row, v.Col),
-			)
-		}
+		p.paintPath(p.path, func(c domain.Coord) domain.CellType {
+			return p.paintMaze.GetCellType(p.data.CellRow(c), c.Col)
+		})
+		p.paintPath(path, func(domain.Coord) domain.CellType {
+			return domain.Path
+		})
 
-		for _, v := range path {
-			p.paint(v.Row, v.Col, domain.Path)
-		}
-
-		prevPath = path
+		p.path = path
 
 		time.Sleep(delay)
 	}
 }
+
+// Floor returns the shown floor.
+func (p *Painter) Floor() int {
+	return p.floor
+}
+
+// ShowFloor repaints the screen with the floor of the given level and the
+// part of the last painted path which lies on it.
+func (p *Painter) ShowFloor(level int) {
+	defer p.moveCursor(p.data.Height+1, -1)
+
+	p.floor = min(max(level, 0), p.data.Floors()-1)
+
+	clearScreen()
+	p.paintStartEnd()
+
+	for row := range p.data.Height {
+		for col := range p.data.Width {
+			p.paintCell(p.floor*p.data.Height+row, col)
+		}
+	}
+
+	p.paintPath(p.path, func(domain.Coord) domain.CellType {
+		return domain.Path
+	})
+}
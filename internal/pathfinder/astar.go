@@ -2,9 +2,10 @@ package pathfinder
 
 import (
 	"container/heap"
-	"math"
+	"context"
 
 	"github.com/LLIEPJIOK/mazegenerator/internal/domain"
+	"github.com/LLIEPJIOK/mazegenerator/internal/registry"
 )
 
 type aStarItem struct {
@@ -29,29 +30,36 @@ func (a aStarItem) dist() int {
 	return a.f
 }
 
-type AStar struct {
-	dir domain.Direction
-}
+type AStar struct{}
 
 func NewAStar() *AStar {
-	return &AStar{
-		dir: domain.DefaultDirection(),
-	}
+	return &AStar{}
 }
 
-// Function for calculating Manhattan distance.
-func heuristic(a, b domain.Coord) int {
-	return int(math.Abs(float64(a.Row-b.Row)) + math.Abs(float64(a.Col-b.Col)))
+// heuristic returns the number of steps between cells: Manhattan distance on
+// square grids and hex distance on hex grids plus the number of floors
+// between them. Every step costs at least 1, so it never overestimates the
+// cost.
+func heuristic(data domain.MazeData, a, b domain.Coord) int {
+	return data.Distance(a, b)
 }
 
-func (a *AStar) ShortestPath(maze domain.Maze, pathChan chan<- []domain.Coord) ([]domain.Coord, bool) {
+func (a *AStar) ShortestPath(
+	ctx context.Context,
+	maze domain.Maze,
+	pathChan chan<- []domain.Coord,
+) ([]domain.Coord, bool, error) {
 	pq := make(priorityQueue, 0)
 	heap.Init(&pq)
-	heap.Push(&pq, newAStarItem(maze.Data.Start, domain.Coord{}, 0, heuristic(maze.Data.Start, maze.Data.End), 0))
+	heap.Push(&pq, newAStarItem(maze.Data.Start, domain.Coord{}, 0, heuristic(maze.Data, maze.Data.Start, maze.Data.End), 0))
 
 	prevCoords := make(map[domain.Coord]domain.Coord)
 
 	for len(pq) != 0 {
+		if err := ctx.Err(); err != nil {
+			return nil, false, err
+		}
+
 		curItem := heap.Pop(&pq).(aStarItem)
 
 		if _, ok := prevCoords[curItem.curCoord]; ok {
@@ -60,28 +68,29 @@ func (a *AStar) ShortestPath(maze domain.Maze, pathChan chan<- []domain.Coord) (
 
 		prevCoords[curItem.curCoord] = curItem.prevCoord
 
-		curPath := getPath(prevCoords, curItem.curCoord, maze.Data.Start)
-		pathChan <- curPath
+		// the path to every cell is built for drawing only, it costs more than the search
+		if pathChan != nil {
+			curPath := getPath(prevCoords, curItem.curCoord, maze.Data.Start)
+			if err := sendPath(ctx, pathChan, curPath); err != nil {
+				return nil, false, err
+			}
+		}
 
 		if curItem.curCoord == maze.Data.End {
 			path := getPath(prevCoords, maze.Data.End, maze.Data.Start)
 
-			return path, true
+			return path, true, nil
 		}
 
-		for i := range a.dir.Cols {
-			dRow, dCol := a.dir.Rows[i], a.dir.Cols[i]
-			newRow, newCol := curItem.curCoord.Row+dRow, curItem.curCoord.Col+dCol
-
-			if min(newCol, newRow) >= 0 && newRow < maze.Data.Height && newCol < maze.Data.Width &&
-				maze.Cells[newRow][newCol].IsTraversable() {
-				newG := curItem.G + maze.Cells[newRow][newCol].Cost()
-				newH := heuristic(curItem.curCoord, domain.NewCoord(newRow, newCol))
+		for _, next := range maze.Data.Neighbours(curItem.curCoord) {
+			if maze.Passable(curItem.curCoord, next) {
+				newG := curItem.G + maze.Cell(next).Cost()
+				newH := heuristic(maze.Data, next, maze.Data.End)
 				newDist := newG + newH
 				heap.Push(
 					&pq,
 					newAStarItem(
-						domain.NewCoord(newRow, newCol),
+						next,
 						curItem.curCoord,
 						newG,
 						newH,
@@ -92,7 +101,17 @@ func (a *AStar) ShortestPath(maze domain.Maze, pathChan chan<- []domain.Coord) (
 		}
 	}
 
-	pathChan <- nil
+	if err := sendPath(ctx, pathChan, nil); err != nil {
+		return nil, false, err
+	}
 
-	return nil, false
+	return nil, false, nil
+}
+
+func aStarEntry() registry.Entry[PathFinder] {
+	return registry.Entry[PathFinder]{
+		Name:        "a-star",
+		Description: "A* guided by the distance to the end",
+		New:         func(registry.Values) PathFinder { return NewAStar() },
+	}
 }
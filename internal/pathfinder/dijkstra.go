@@ -2,8 +2,10 @@ package pathfinder
 
 import (
 	"container/heap"
+	"context"
 
 	"github.com/LLIEPJIOK/mazegenerator/internal/domain"
+	"github.com/LLIEPJIOK/mazegenerator/internal/registry"
 )
 
 type dijkstraItem struct {
@@ -24,17 +26,17 @@ func (d dijkstraItem) dist() int {
 	return d.distance
 }
 
-type Dijkstra struct {
-	dir domain.Direction
-}
+type Dijkstra struct{}
 
 func NewDijkstra() *Dijkstra {
-	return &Dijkstra{
-		dir: domain.DefaultDirection(),
-	}
+	return &Dijkstra{}
 }
 
-func (d *Dijkstra) ShortestPath(maze domain.Maze, pathChan chan<- []domain.Coord) ([]domain.Coord, bool) {
+func (d *Dijkstra) ShortestPath(
+	ctx context.Context,
+	maze domain.Maze,
+	pathChan chan<- []domain.Coord,
+) ([]domain.Coord, bool, error) {
 	pq := make(priorityQueue, 0)
 	heap.Init(&pq)
 	heap.Push(&pq, newDijkstraItem(maze.Data.Start, domain.Coord{}, 0))
@@ -42,6 +44,10 @@ func (d *Dijkstra) ShortestPath(maze domain.Maze, pathChan chan<- []domain.Coord
 	prevCoords := make(map[domain.Coord]domain.Coord)
 
 	for len(pq) != 0 {
+		if err := ctx.Err(); err != nil {
+			return nil, false, err
+		}
+
 		curItem := heap.Pop(&pq).(dijkstraItem)
 
 		if _, ok := prevCoords[curItem.curCoord]; ok {
@@ -50,34 +56,45 @@ func (d *Dijkstra) ShortestPath(maze domain.Maze, pathChan chan<- []domain.Coord
 
 		prevCoords[curItem.curCoord] = curItem.prevCoord
 
-		curPath := getPath(prevCoords, curItem.curCoord, maze.Data.Start)
-		pathChan <- curPath
+		// the path to every cell is built for drawing only, it costs more than the search
+		if pathChan != nil {
+			curPath := getPath(prevCoords, curItem.curCoord, maze.Data.Start)
+			if err := sendPath(ctx, pathChan, curPath); err != nil {
+				return nil, false, err
+			}
+		}
 
 		if curItem.curCoord == maze.Data.End {
 			path := getPath(prevCoords, maze.Data.End, maze.Data.Start)
 
-			return path, true
+			return path, true, nil
 		}
 
-		for i := range d.dir.Cols {
-			dRow, dCol := d.dir.Rows[i], d.dir.Cols[i]
-			newRow, newCol := curItem.curCoord.Row+dRow, curItem.curCoord.Col+dCol
-
-			if min(newCol, newRow) >= 0 && newRow < maze.Data.Height && newCol < maze.Data.Width &&
-				maze.Cells[newRow][newCol].IsTraversable() {
+		for _, next := range maze.Data.Neighbours(curItem.curCoord) {
+			if maze.Passable(curItem.curCoord, next) {
 				heap.Push(
 					&pq,
 					newDijkstraItem(
-						domain.NewCoord(newRow, newCol),
+						next,
 						curItem.curCoord,
-						curItem.distance+maze.Cells[newRow][newCol].Cost(),
+						curItem.distance+maze.Cell(next).Cost(),
 					),
 				)
 			}
 		}
 	}
 
-	pathChan <- nil
+	if err := sendPath(ctx, pathChan, nil); err != nil {
+		return nil, false, err
+	}
 
-	return nil, false
+	return nil, false, nil
+}
+
+func dijkstraEntry() registry.Entry[PathFinder] {
+	return registry.Entry[PathFinder]{
+		Name:        "dijkstra",
+		Description: "Dijkstra's algorithm",
+		New:         func(registry.Values) PathFinder { return NewDijkstra() },
+	}
 }
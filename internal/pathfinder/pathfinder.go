@@ -1,6 +1,7 @@
 package pathfinder
 
 import (
+	"context"
 	"slices"
 
 	"github.com/LLIEPJIOK/mazegenerator/internal/domain"
@@ -23,3 +24,18 @@ func getPath(
 
 	return path
 }
+
+// sendPath passes the path to pathChan unless ctx is done first. A nil
+// channel means the search isn't drawn.
+func sendPath(ctx context.Context, pathChan chan<- []domain.Coord, path []domain.Coord) error {
+	if pathChan == nil {
+		return ctx.Err()
+	}
+
+	select {
+	case pathChan <- path:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -1,6 +1,7 @@
 package pathfinder_test
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -9,10 +10,6 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
-type PathFinder interface {
-	ShortestPath(maze domain.Maze, pathChan chan<- []domain.Coord) ([]domain.Coord, bool)
-}
-
 func squareDist(first, second domain.Coord) int {
 	dRow, dCol := first.Row-second.Row, first.Col-second.Col
 
@@ -37,7 +34,7 @@ func TestFindPathIfPathExists(t *testing.T) {
 	testCases := []struct {
 		data         domain.MazeData
 		cells        [][]domain.CellType
-		pathFinder   PathFinder
+		pathFinder   pathfinder.PathFinder
 		shortestDist int
 	}{
 		{
@@ -134,7 +131,8 @@ func TestFindPathIfPathExists(t *testing.T) {
 			t.Parallel()
 
 			maze := domain.NewMaze(testCase.data, testCase.cells)
-			path, ok := testCase.pathFinder.ShortestPath(maze, discardChan())
+			path, ok, err := testCase.pathFinder.ShortestPath(context.Background(), maze, discardChan())
+			require.NoError(t, err, "path finding should return nil error")
 
 			require.True(t, ok, "path must exist")
 			require.NotEqual(t, 0, len(path), "path mustn't be empty")
@@ -170,7 +168,7 @@ func TestFindPathIfPathNotExists(t *testing.T) {
 	testCases := []struct {
 		data       domain.MazeData
 		cells      [][]domain.CellType
-		pathFinder PathFinder
+		pathFinder pathfinder.PathFinder
 	}{
 		{
 			data: domain.NewMazeData(5, 5, domain.NewCoord(0, 0), domain.NewCoord(4, 4)),
@@ -211,9 +209,296 @@ func TestFindPathIfPathNotExists(t *testing.T) {
 	for i, testCase := range testCases {
 		t.Run(fmt.Sprintf("#%d", i+1), func(t *testing.T) {
 			maze := domain.NewMaze(testCase.data, testCase.cells)
-			_, ok := testCase.pathFinder.ShortestPath(maze, discardChan())
+			_, ok, err := testCase.pathFinder.ShortestPath(context.Background(), maze, discardChan())
+			require.NoError(t, err, "path finding should return nil error")
 
 			require.False(t, ok, "path mustn't exist")
 		})
 	}
 }
+
+func hexMazeData(height, width int, start, end domain.Coord) domain.MazeData {
+	data := domain.NewMazeData(height, width, start, end)
+	data.Topology = domain.Hex
+
+	return data
+}
+
+func TestFindPathOnHexGrid(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		data         domain.MazeData
+		cells        [][]domain.CellType
+		pathFinder   pathfinder.PathFinder
+		shortestDist int
+	}{
+		{
+			data: hexMazeData(3, 3, domain.NewCoord(0, 0), domain.NewCoord(2, 1)),
+			cells: [][]domain.CellType{
+				{1, 0, 0},
+				{1, 0, 0},
+				{0, 1, 0},
+			},
+			pathFinder:   pathfinder.NewDijkstra(),
+			shortestDist: 6,
+		},
+		{
+			data: hexMazeData(3, 3, domain.NewCoord(0, 0), domain.NewCoord(2, 1)),
+			cells: [][]domain.CellType{
+				{1, 0, 0},
+				{1, 0, 0},
+				{0, 1, 0},
+			},
+			pathFinder:   pathfinder.NewAStar(),
+			shortestDist: 6,
+		},
+		{
+			data: hexMazeData(4, 4, domain.NewCoord(0, 0), domain.NewCoord(3, 3)),
+			cells: [][]domain.CellType{
+				{1, 1, 1, 1},
+				{0, 0, 2, 4},
+				{0, 0, 1, 1},
+				{0, 0, 0, 1},
+			},
+			pathFinder:   pathfinder.NewDijkstra(),
+			shortestDist: 13,
+		},
+		{
+			data: hexMazeData(4, 4, domain.NewCoord(0, 0), domain.NewCoord(3, 3)),
+			cells: [][]domain.CellType{
+				{1, 1, 1, 1},
+				{0, 0, 2, 4},
+				{0, 0, 1, 1},
+				{0, 0, 0, 1},
+			},
+			pathFinder:   pathfinder.NewAStar(),
+			shortestDist: 13,
+		},
+	}
+
+	for i, testCase := range testCases {
+		t.Run(fmt.Sprintf("#%d", i+1), func(t *testing.T) {
+			t.Parallel()
+
+			maze := domain.NewMaze(testCase.data, testCase.cells)
+			path, ok, err := testCase.pathFinder.ShortestPath(context.Background(), maze, discardChan())
+			require.NoError(t, err, "path finding should return nil error")
+
+			require.True(t, ok, "path must exist")
+			require.Equal(t, testCase.data.Start, path[0], "path must start from start point")
+			require.Equal(t, testCase.data.End, path[len(path)-1], "path must end in end point")
+
+			dist := 0
+
+			for i := 1; i < len(path); i++ {
+				require.Equal(t, 1, domain.Hex.Distance(path[i-1], path[i], maze.Data.Height, maze.Data.Width), "path must be connected")
+
+				dist += maze.Cells[path[i].Row][path[i].Col].Cost()
+			}
+
+			require.Equal(t, testCase.shortestDist, dist, "invalid shortest path")
+		})
+	}
+}
+
+func TestFindPathOnPolarGrid(t *testing.T) {
+	t.Parallel()
+
+	// rings of 1, 6 and 12 cells: the end is reached straight through the river
+	// or round it along the outer ring
+	cells := [][]domain.CellType{
+		{1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
+		{4, 0, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0},
+		{1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1},
+	}
+
+	testCases := []struct {
+		pathFinder   pathfinder.PathFinder
+		shortestDist int
+	}{
+		{pathFinder: pathfinder.NewDijkstra(), shortestDist: 9},
+		{pathFinder: pathfinder.NewAStar(), shortestDist: 9},
+	}
+
+	for i, testCase := range testCases {
+		t.Run(fmt.Sprintf("#%d", i+1), func(t *testing.T) {
+			t.Parallel()
+
+			maze := domain.NewMaze(domain.NewPolarMazeData(3), cells)
+			path, ok, err := testCase.pathFinder.ShortestPath(context.Background(), maze, discardChan())
+			require.NoError(t, err, "path finding should return nil error")
+
+			require.True(t, ok, "path must exist")
+			require.Equal(t, maze.Data.Start, path[0], "path must start from start point")
+			require.Equal(t, maze.Data.End, path[len(path)-1], "path must end in end point")
+
+			dist := 0
+
+			for i := 1; i < len(path); i++ {
+				require.Contains(
+					t,
+					domain.Polar.Neighbours(path[i-1], maze.Data.Height, maze.Data.Width),
+					path[i],
+					"path must be connected",
+				)
+
+				dist += maze.Cells[path[i].Row][path[i].Col].Cost()
+			}
+
+			require.Equal(t, testCase.shortestDist, dist, "invalid shortest path")
+		})
+	}
+}
+
+func TestFindPathOnLevels(t *testing.T) {
+	t.Parallel()
+
+	s := domain.Stairs
+
+	// two floors of 3×3 cells: the passages in the left column lie right above
+	// each other, but only stairs lead to the upper floor
+	cells := [][]domain.CellType{
+		{1, 1, 1},
+		{1, 0, s},
+		{1, 0, 0},
+		{0, 0, 0},
+		{0, 0, s},
+		{1, 1, 1},
+	}
+
+	testCases := []struct {
+		pathFinder   pathfinder.PathFinder
+		shortestDist int
+	}{
+		{pathFinder: pathfinder.NewDijkstra(), shortestDist: 25},
+		{pathFinder: pathfinder.NewAStar(), shortestDist: 25},
+	}
+
+	for i, testCase := range testCases {
+		t.Run(fmt.Sprintf("#%d", i+1), func(t *testing.T) {
+			t.Parallel()
+
+			data := domain.NewLevelMazeData(2, 3, 3, domain.NewCoord(0, 0), domain.NewCoord(2, 0))
+			maze := domain.NewMaze(data, cells)
+			path, ok, err := testCase.pathFinder.ShortestPath(context.Background(), maze, discardChan())
+			require.NoError(t, err, "path finding should return nil error")
+
+			require.True(t, ok, "path must exist")
+			require.Equal(t, domain.NewLevelCoord(1, 2, 0), path[len(path)-1], "path must end on the upper floor")
+
+			dist := 0
+
+			for i := 1; i < len(path); i++ {
+				require.True(t, maze.Passable(path[i-1], path[i]), "path must be passable")
+
+				dist += maze.Cell(path[i]).Cost()
+			}
+
+			require.Equal(t, testCase.shortestDist, dist, "invalid shortest path")
+		})
+	}
+}
+
+func TestFindPathOnTorus(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		pathFinder   pathfinder.PathFinder
+		data         domain.MazeData
+		cells        [][]domain.CellType
+		shortestDist int
+	}{
+		{
+			pathFinder: pathfinder.NewDijkstra(),
+			data:       domain.NewMazeData(4, 5, domain.NewCoord(0, 0), domain.NewCoord(0, 4)),
+			cells: [][]domain.CellType{
+				{1, 0, 0, 0, 1},
+				{1, 1, 1, 1, 1},
+				{0, 0, 0, 0, 0},
+				{0, 0, 0, 0, 0},
+			},
+			shortestDist: 3,
+		},
+		{
+			pathFinder: pathfinder.NewAStar(),
+			data:       domain.NewMazeData(4, 5, domain.NewCoord(0, 0), domain.NewCoord(0, 4)),
+			cells: [][]domain.CellType{
+				{1, 0, 0, 0, 1},
+				{1, 1, 1, 1, 1},
+				{0, 0, 0, 0, 0},
+				{0, 0, 0, 0, 0},
+			},
+			shortestDist: 3,
+		},
+		{
+			pathFinder: pathfinder.NewAStar(),
+			data:       domain.NewMazeData(5, 4, domain.NewCoord(0, 1), domain.NewCoord(4, 2)),
+			cells: [][]domain.CellType{
+				{0, 1, 0, 0},
+				{0, 1, 0, 0},
+				{0, 1, 1, 0},
+				{0, 0, 1, 0},
+				{0, 4, 1, 0},
+			},
+			shortestDist: 13,
+		},
+	}
+
+	for i, testCase := range testCases {
+		t.Run(fmt.Sprintf("#%d", i+1), func(t *testing.T) {
+			t.Parallel()
+
+			testCase.data.Topology = domain.Torus
+			maze := domain.NewMaze(testCase.data, testCase.cells)
+			path, ok, err := testCase.pathFinder.ShortestPath(context.Background(), maze, discardChan())
+			require.NoError(t, err, "path finding should return nil error")
+
+			require.True(t, ok, "path must exist")
+			require.Equal(t, testCase.data.End, path[len(path)-1], "path must end in end point")
+
+			dist := 0
+
+			for i := 1; i < len(path); i++ {
+				require.Contains(t, maze.Data.Neighbours(path[i-1]), path[i], "path must be connected")
+
+				dist += maze.Cell(path[i]).Cost()
+			}
+
+			require.Equal(t, testCase.shortestDist, dist, "invalid shortest path")
+		})
+	}
+}
+
+func TestFindPathWithCancelledContext(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		pathFinder pathfinder.PathFinder
+	}{
+		{pathFinder: pathfinder.NewDijkstra()},
+		{pathFinder: pathfinder.NewAStar()},
+	}
+
+	for i, testCase := range testCases {
+		t.Run(fmt.Sprintf("#%d", i+1), func(t *testing.T) {
+			t.Parallel()
+
+			data := domain.NewMazeData(3, 3, domain.NewCoord(0, 0), domain.NewCoord(2, 2))
+			maze := domain.NewMaze(data, [][]domain.CellType{
+				{1, 1, 1},
+				{1, 1, 1},
+				{1, 1, 1},
+			})
+
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			// nobody reads the channel, so the search mustn't block on it
+			_, ok, err := testCase.pathFinder.ShortestPath(ctx, maze, make(chan []domain.Coord))
+
+			require.ErrorIs(t, err, context.Canceled, "path finding should be cancelled")
+			require.False(t, ok, "cancelled search mustn't find a path")
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package pathfinder
+
+import (
+	"context"
+
+	"github.com/LLIEPJIOK/mazegenerator/internal/domain"
+	"github.com/LLIEPJIOK/mazegenerator/internal/registry"
+)
+
+// PathFinder finds the cheapest path from the start of the maze to its end.
+// Paths explored so far are sent to pathChan, which may be nil.
+type PathFinder interface {
+	ShortestPath(
+		ctx context.Context,
+		maze domain.Maze,
+		pathChan chan<- []domain.Coord,
+	) ([]domain.Coord, bool, error)
+}
+
+// NewRegistry returns all path finding algorithms in the order they are listed in.
+// Every algorithm declares its entry next to its implementation, the list
+// only sets the order of the menus.
+func NewRegistry() *registry.Registry[PathFinder] {
+	reg := registry.New[PathFinder]()
+
+	for _, entry := range []registry.Entry[PathFinder]{
+		dijkstraEntry(),
+		aStarEntry(),
+	} {
+		// names above are unique, so registering never fails
+		_ = reg.Register(entry)
+	}
+
+	return reg
+}
@@ -23,3 +23,45 @@ func NewErrInvalidRange(mn, mx int) ErrInvalidRange {
 func (e ErrInvalidRange) Error() string {
 	return fmt.Sprintf("range [%d, %d] is invalid", e.mn, e.mx)
 }
+
+type ErrUnknownTerrain struct {
+	name string
+}
+
+func NewErrUnknownTerrain(name string) ErrUnknownTerrain {
+	return ErrUnknownTerrain{
+		name: name,
+	}
+}
+
+func (e ErrUnknownTerrain) Error() string {
+	return fmt.Sprintf("unknown terrain profile %q", e.name)
+}
+
+type ErrUnknownTopology struct {
+	name string
+}
+
+func NewErrUnknownTopology(name string) ErrUnknownTopology {
+	return ErrUnknownTopology{
+		name: name,
+	}
+}
+
+func (e ErrUnknownTopology) Error() string {
+	return fmt.Sprintf("unknown grid topology %q", e.name)
+}
+
+type ErrUnknownMerge struct {
+	name string
+}
+
+func NewErrUnknownMerge(name string) ErrUnknownMerge {
+	return ErrUnknownMerge{
+		name: name,
+	}
+}
+
+func (e ErrUnknownMerge) Error() string {
+	return fmt.Sprintf("unknown merge strategy %q", e.name)
+}
@@ -4,10 +4,15 @@ import (
 	"bufio"
 	"fmt"
 	"io"
+	"math/rand/v2"
 	"strconv"
 	"strings"
 
 	"github.com/LLIEPJIOK/mazegenerator/internal/domain"
+	"github.com/LLIEPJIOK/mazegenerator/internal/generator"
+	"github.com/LLIEPJIOK/mazegenerator/internal/mask"
+	"github.com/LLIEPJIOK/mazegenerator/internal/pathfinder"
+	"github.com/LLIEPJIOK/mazegenerator/internal/registry"
 )
 
 const (
@@ -15,14 +20,25 @@ const (
 
 Before we start, please keep the following in mind:
  - To display the maze correctly, you must enter the dimensions so that the maze fits into the console
- - The start and end points must be on the boundaries of the maze
+ - The start and end points must be on the boundaries of the maze or its mask. Polar mazes start at the centre
+   and end at the rim
+ - A mask is a .png image, where dark pixels are cells of the maze, or a text file, where '.' and spaces are
+   cells outside the maze
  - Maze width and height must be >= 2. For smaller values, we get a simple labyrinth in which the path 
    from the start point to the end point is clearly found
+ - Multi-level mazes start on the ground floor and end on the top floor. One floor is shown at a time
+ - Edges of torus mazes wrap around, the dotted border marks them
 
 `
 
-	generationAlgorithms = "prim backtrack"
-	pathFinderAlgorithms = "dijkstra a-star"
+	terrainProfiles = "default desert swamp treasure-hunt"
+	terrainLayouts  = "scattered biomes"
+	gridTopologies  = "square hex polar torus"
+	mergeStrategies = "random cheapest start-side seam interleave"
+
+	// maxWorkers limits the number of partial mazes, each of them takes as
+	// much memory as the whole maze.
+	maxWorkers = 16
 )
 
 type Input struct {
@@ -32,16 +48,51 @@ type Input struct {
 	End          domain.Coord
 	GenAlgo      string
 	PathFindAlgo string
+	Seed         uint64
+	Terrain      domain.TerrainProfile
+	Biomes       bool
+	Braid        float64
+	// GenParams are parameters of the generation algorithm by their names.
+	GenParams map[string]string
+	// PathFindParams are parameters of the path finding algorithm by their names.
+	PathFindParams map[string]string
+	Topology       domain.Topology
+	Levels         int
+	// Mask is the shape of the maze, nil for a rectangular maze.
+	Mask domain.Mask
+	// Difficulty is the minimum difficulty of the maze, zero for any maze.
+	Difficulty generator.Difficulty
+	// Workers is the number of partial mazes generated concurrently.
+	Workers int
+	// Merge settles cells carved by several workers.
+	Merge generator.MergeStrategy
 }
 
-func NewInput(height, width int, start, end domain.Coord, genAlgo, pathFindAlgo string) *Input {
+func NewInput(
+	height, width int,
+	start, end domain.Coord,
+	genAlgo, pathFindAlgo string,
+	seed uint64,
+	terrain domain.TerrainProfile,
+	biomes bool,
+	braid float64,
+) *Input {
 	return &Input{
-		Height:       height,
-		Width:        width,
-		Start:        start,
-		End:          end,
-		GenAlgo:      genAlgo,
-		PathFindAlgo: pathFindAlgo,
+		Height:         height,
+		Width:          width,
+		Start:          start,
+		End:            end,
+		GenAlgo:        genAlgo,
+		PathFindAlgo:   pathFindAlgo,
+		Seed:           seed,
+		Terrain:        terrain,
+		Biomes:         biomes,
+		Braid:          braid,
+		GenParams:      map[string]string{},
+		PathFindParams: map[string]string{},
+		Levels:         1,
+		Workers:        generator.DefaultWorkers,
+		Merge:          generator.MergeRandom,
 	}
 }
 
@@ -50,28 +101,89 @@ type dimension struct {
 	width  int
 }
 
+// settings are the answers which tune the generator whatever the algorithm.
+type settings struct {
+	seed       uint64
+	terrain    domain.TerrainProfile
+	biomes     bool
+	braid      float64
+	difficulty generator.Difficulty
+	workers    int
+	merge      generator.MergeStrategy
+}
+
 type Presentation struct {
 	in            io.Reader
+	scan          *bufio.Scanner
 	out           io.Writer
-	genAlgos      []string
-	pathFindAlgos []string
+	genAlgos      *registry.Registry[generator.Algorithm]
+	pathFindAlgos *registry.Registry[pathfinder.PathFinder]
+	terrains      []string
+	layouts       []string
+	topologies    []string
+	merges        []string
 }
 
 func New(in io.Reader, out io.Writer) *Presentation {
 	return &Presentation{
 		in:            in,
+		scan:          bufio.NewScanner(in),
 		out:           out,
-		genAlgos:      strings.Fields(generationAlgorithms),
-		pathFindAlgos: strings.Fields(pathFinderAlgorithms),
+		genAlgos:      generator.NewRegistry(),
+		pathFindAlgos: pathfinder.NewRegistry(),
+		terrains:      strings.Fields(terrainProfiles),
+		layouts:       strings.Fields(terrainLayouts),
+		topologies:    strings.Fields(gridTopologies),
+		merges:        strings.Fields(mergeStrategies),
 	}
 }
 
-func (p *Presentation) writeCellsInfo() {
+func cellTypeName(tpe domain.CellType) string {
+	switch tpe {
+	case domain.Passage:
+		return "Passage"
+	case domain.Money:
+		return "Money"
+	case domain.Sand:
+		return "Sand"
+	case domain.River:
+		return "River"
+	case domain.Stairs:
+		return "Stairs"
+	case domain.Wall, domain.Ambiguous, domain.Path:
+	}
+
+	return ""
+}
+
+func (p *Presentation) writeCellsInfo(terrain domain.TerrainProfile, levels int) {
 	fmt.Fprintln(p.out, "During the maze generation you can see the following cells:")
-	fmt.Fprintf(p.out, " %s - Passage.\tCost = %d\n", domain.Passage, domain.Passage.Cost())
-	fmt.Fprintf(p.out, " %s - Money.\tCost = %d\n", domain.Money, domain.Money.Cost())
-	fmt.Fprintf(p.out, " %s - Sand.\tCost = %d\n", domain.Sand, domain.Sand.Cost())
-	fmt.Fprintf(p.out, " %s - River.\tCost = %d\n", domain.River, domain.River.Cost())
+
+	for _, tpe := range domain.TerrainTypes() {
+		if terrain.Share(tpe) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(
+			p.out,
+			" %s - %s.\tCost = %d\tShare = %d%%\n",
+			tpe,
+			cellTypeName(tpe),
+			tpe.Cost(),
+			terrain.Share(tpe),
+		)
+	}
+
+	if levels > 1 {
+		fmt.Fprintf(
+			p.out,
+			" %s - %s to the adjacent floor.\tCost = %d\n",
+			domain.Stairs,
+			cellTypeName(domain.Stairs),
+			domain.Stairs.Cost(),
+		)
+	}
+
 	fmt.Fprintf(p.out, " %s - Path.\n", domain.Path)
 	fmt.Fprintf(p.out, " %s - Ambiguous. Its type will be defined further\n\n", domain.Ambiguous)
 }
@@ -176,19 +288,18 @@ func (p *Presentation) point(scan *bufio.Scanner, pointName string, dim dimensio
 	return coord, nil
 }
 
-func (p *Presentation) startCoord(scan *bufio.Scanner, dim dimension) (domain.Coord, error) {
+func (p *Presentation) startCoord(scan *bufio.Scanner, data domain.MazeData) (domain.Coord, error) {
 	var start domain.Coord
 
 	var err error
 
 	for {
-		start, err = p.point(scan, "start", dim)
+		start, err = p.point(scan, "start", dimension{height: data.Height, width: data.Width})
 		if err != nil {
 			return domain.Coord{}, fmt.Errorf("get start point: %w", err)
 		}
 
-		if start.Row != 0 && start.Row != dim.height-1 && start.Col != 0 &&
-			start.Col != dim.width-1 {
+		if !data.OnBoundary(start) {
 			fmt.Fprintln(
 				p.out,
 				"\033[31mError: start point must lie on the boundary.\033[0m\nType correct start point!",
@@ -201,14 +312,14 @@ func (p *Presentation) startCoord(scan *bufio.Scanner, dim dimension) (domain.Co
 	return start, nil
 }
 
-func (p *Presentation) endCoord(scan *bufio.Scanner, dim dimension, start domain.Coord) (domain.Coord, error) {
+func (p *Presentation) endCoord(scan *bufio.Scanner, data domain.MazeData, start domain.Coord) (domain.Coord, error) {
 	var end domain.Coord
 
 	var err error
 
 CoordLoop:
 	for {
-		end, err = p.point(scan, "end", dim)
+		end, err = p.point(scan, "end", dimension{height: data.Height, width: data.Width})
 		if err != nil {
 			return domain.Coord{}, fmt.Errorf("get end point: %w", err)
 		}
@@ -220,9 +331,15 @@ CoordLoop:
 				"\033[31mError: start and end points are equal.\033[0m\nType correct end point!",
 			)
 
-		case end.Row != 0 && end.Row != dim.height-1 && end.Col != 0 && end.Col != dim.width-1:
+		case !data.OnBoundary(end):
 			fmt.Fprintln(p.out, "\033[31mError: end point must lie on the boundary.\033[0m\nType correct end point!")
 
+		case !data.Connected(start, end):
+			fmt.Fprintln(
+				p.out,
+				"\033[31mError: end point must be reachable from the start through the mask.\033[0m\nType correct end point!",
+			)
+
 		default:
 			break CoordLoop
 		}
@@ -231,77 +348,576 @@ CoordLoop:
 	return end, nil
 }
 
-func (p *Presentation) algorithm(scan *bufio.Scanner, algos []string) (string, error) {
-	for i, algo := range algos {
-		fmt.Fprintf(p.out, " %d. %s\n", i+1, algo)
+func (p *Presentation) menu(scan *bufio.Scanner, items []string) (string, error) {
+	for i, item := range items {
+		fmt.Fprintf(p.out, " %d. %s\n", i+1, item)
 	}
 
-	rng, err := newRange(newRangePoint(1, true), newRangePoint(len(algos), true))
+	item, err := p.menuItem(scan, len(items))
 	if err != nil {
-		return "", fmt.Errorf("create range: %w", err)
+		return "", err
 	}
 
-	algo, err := p.getInt(scan, rng)
+	return items[item], nil
+}
+
+// menuItem reads the number of the chosen item and returns its index.
+func (p *Presentation) menuItem(scan *bufio.Scanner, items int) (int, error) {
+	rng, err := newRange(newRangePoint(1, true), newRangePoint(items, true))
 	if err != nil {
-		return "", fmt.Errorf("read generation algorithm from input stream: %w", err)
+		return 0, fmt.Errorf("create range: %w", err)
 	}
 
-	return algos[algo-1], nil
+	item, err := p.getInt(scan, rng)
+	if err != nil {
+		return 0, fmt.Errorf("read menu item from input stream: %w", err)
+	}
+
+	return item - 1, nil
 }
 
-func (p *Presentation) generationAlgorithm(scan *bufio.Scanner) (string, error) {
+// algorithmMenu lists the algorithms with their descriptions and returns the chosen one.
+func algorithmMenu[T any](p *Presentation, scan *bufio.Scanner, entries []registry.Entry[T]) (registry.Entry[T], error) {
+	for i, entry := range entries {
+		fmt.Fprintf(p.out, " %d. %s - %s\n", i+1, entry.Name, entry.Description)
+	}
+
+	item, err := p.menuItem(scan, len(entries))
+	if err != nil {
+		return registry.Entry[T]{}, err
+	}
+
+	return entries[item], nil
+}
+
+// algorithmParams asks for every parameter of the algorithm. Parameters
+// validate values themselves, so invalid ones are asked for again.
+func (p *Presentation) algorithmParams(scan *bufio.Scanner, params []registry.Param) (map[string]string, error) {
+	values := make(map[string]string, len(params))
+
+	for _, param := range params {
+		if param.Default == "" {
+			fmt.Fprintf(p.out, "Enter %s: ", param.Description)
+		} else {
+			fmt.Fprintf(p.out, "Enter %s (leave empty for %q): ", param.Description, param.Default)
+		}
+
+		for {
+			if !scan.Scan() {
+				return nil, ErrNoInputLines{}
+			}
+
+			value := strings.TrimSpace(scan.Text())
+			if value == "" {
+				value = param.Default
+			}
+
+			if _, err := param.Parse(value); err != nil {
+				// ANSI code for red letters
+				fmt.Fprintf(p.out, "\033[31mError: %s.\033[0m\nType a valid %s: ", err, param.Description)
+				continue
+			}
+
+			values[param.Name] = value
+
+			break
+		}
+	}
+
+	return values, nil
+}
+
+func (p *Presentation) rings(scan *bufio.Scanner) (int, error) {
+	fmt.Fprint(p.out, "Enter number of maze rings: ")
+
+	rng, err := newRange(newRangePoint(2, true), newRangePoint(0, false))
+	if err != nil {
+		return 0, fmt.Errorf("create range: %w", err)
+	}
+
+	rings, err := p.getInt(scan, rng)
+	if err != nil {
+		return 0, fmt.Errorf("read rings from input stream: %w", err)
+	}
+
+	return rings, nil
+}
+
+func (p *Presentation) levels(scan *bufio.Scanner) (int, error) {
+	fmt.Fprint(p.out, "Enter number of maze levels: ")
+
+	rng, err := newRange(newRangePoint(1, true), newRangePoint(0, false))
+	if err != nil {
+		return 0, fmt.Errorf("create range: %w", err)
+	}
+
+	levels, err := p.getInt(scan, rng)
+	if err != nil {
+		return 0, fmt.Errorf("read levels from input stream: %w", err)
+	}
+
+	return levels, nil
+}
+
+// generationAlgorithm lists only the algorithms which can generate the maze.
+func (p *Presentation) generationAlgorithm(
+	scan *bufio.Scanner,
+	data domain.MazeData,
+) (registry.Entry[generator.Algorithm], error) {
 	fmt.Fprintln(p.out, "Choose maze generation algorithm:")
 
-	algo, err := p.algorithm(scan, p.genAlgos)
+	algo, err := algorithmMenu(p, scan, p.genAlgos.Supported(data))
 	if err != nil {
-		return "", fmt.Errorf("p.algorithm(scan, %v): %w", p.genAlgos, err)
+		return registry.Entry[generator.Algorithm]{}, fmt.Errorf("choosing generation algorithm: %w", err)
 	}
 
 	return algo, nil
 }
 
-func (p *Presentation) pathFinderAlgorithm(scan *bufio.Scanner) (string, error) {
+func (p *Presentation) pathFinderAlgorithm(
+	scan *bufio.Scanner,
+	data domain.MazeData,
+) (registry.Entry[pathfinder.PathFinder], error) {
 	fmt.Fprintln(p.out, "Choose path finder generation algorithm:")
 
-	algo, err := p.algorithm(scan, p.pathFindAlgos)
+	algo, err := algorithmMenu(p, scan, p.pathFindAlgos.Supported(data))
 	if err != nil {
-		return "", fmt.Errorf("p.algorithm(scan, %v): %w", p.pathFindAlgos, err)
+		return registry.Entry[pathfinder.PathFinder]{}, fmt.Errorf("choosing path finder algorithm: %w", err)
 	}
 
 	return algo, nil
 }
 
-func (p *Presentation) ProcessInput() (*Input, error) {
-	fmt.Fprint(p.out, greetingMessage)
-	p.writeCellsInfo()
-	fmt.Print("Enjoy the program!\n\n")
+func (p *Presentation) seed(scan *bufio.Scanner) (uint64, error) {
+	fmt.Fprint(p.out, "Enter generation seed (leave empty for a random one): ")
 
-	scan := bufio.NewScanner(p.in)
+	for {
+		if !scan.Scan() {
+			return 0, ErrNoInputLines{}
+		}
 
-	dim, err := p.mazeDimension(scan)
+		inputLine := strings.TrimSpace(scan.Text())
+		if inputLine == "" {
+			return rand.Uint64(), nil //nolint:gosec // the seed isn't used for security purposes
+		}
+
+		seed, err := strconv.ParseUint(inputLine, 10, 64)
+		if err != nil {
+			// ANSI code for red letters
+			fmt.Fprintf(p.out, "\033[31mError: %s.\033[0m\nType a non-negative integer or leave it empty: ", err)
+			continue
+		}
+
+		return seed, nil
+	}
+}
+
+func (p *Presentation) terrain(scan *bufio.Scanner) (domain.TerrainProfile, error) {
+	fmt.Fprintln(p.out, "Choose terrain profile:")
+
+	name, err := p.menu(scan, p.terrains)
 	if err != nil {
-		return nil, fmt.Errorf("getting dimension: %w", err)
+		return nil, fmt.Errorf("p.menu(scan, %v): %w", p.terrains, err)
+	}
+
+	terrain, ok := domain.TerrainPreset(name)
+	if !ok {
+		return nil, NewErrUnknownTerrain(name)
 	}
 
-	start, err := p.startCoord(scan, dim)
+	return terrain, nil
+}
+
+func (p *Presentation) biomes(scan *bufio.Scanner) (bool, error) {
+	fmt.Fprintln(p.out, "Choose terrain layout:")
+
+	layout, err := p.menu(scan, p.layouts)
 	if err != nil {
-		return nil, fmt.Errorf("getting start coord: %w", err)
+		return false, fmt.Errorf("p.menu(scan, %v): %w", p.layouts, err)
+	}
+
+	return layout == "biomes", nil
+}
+
+func (p *Presentation) braid(scan *bufio.Scanner) (float64, error) {
+	fmt.Fprintf(
+		p.out,
+		"Enter share of dead ends to turn into loops from 0 (keep all dead ends) to 1 (no dead ends), leave empty for %g: ",
+		generator.DefaultBraid,
+	)
+
+	for {
+		if !scan.Scan() {
+			return 0, ErrNoInputLines{}
+		}
+
+		inputLine := strings.TrimSpace(scan.Text())
+		if inputLine == "" {
+			return generator.DefaultBraid, nil
+		}
+
+		braid, err := strconv.ParseFloat(inputLine, 64)
+
+		switch {
+		case err != nil:
+			// ANSI code for red letters
+			fmt.Fprintf(p.out, "\033[31mError: %s.\033[0m\nType a single number: ", err)
+		case braid < 0 || braid > 1:
+			// ANSI code for red letters
+			fmt.Fprint(p.out, "\033[31mError: Number should be in range [0, 1].\033[0m\nType a valid number: ")
+		default:
+			return braid, nil
+		}
 	}
+}
 
-	end, err := p.endCoord(scan, dim, start)
+func (p *Presentation) difficulty(scan *bufio.Scanner) (generator.Difficulty, error) {
+	fmt.Fprintf(
+		p.out,
+		"Enter minimum difficulty: path cost, decisions on the path and share of dead ends like %q (leave empty for any): ",
+		"cost 120, decisions 8, dead-ends 0.2",
+	)
+
+	for {
+		if !scan.Scan() {
+			return generator.Difficulty{}, ErrNoInputLines{}
+		}
+
+		inputLine := strings.TrimSpace(scan.Text())
+		if inputLine == "" {
+			return generator.Difficulty{}, nil
+		}
+
+		difficulty, err := generator.ParseDifficulty(inputLine)
+		if err != nil {
+			// ANSI code for red letters
+			fmt.Fprintf(p.out, "\033[31mError: %s.\033[0m\nType a valid difficulty: ", err)
+			continue
+		}
+
+		return difficulty, nil
+	}
+}
+
+func (p *Presentation) workers(scan *bufio.Scanner) (int, error) {
+	fmt.Fprintf(
+		p.out,
+		"Enter number of partial mazes generated concurrently from %d to %d, "+
+			"algorithms building the whole maze at once use one (leave empty for %d): ",
+		generator.DefaultWorkers,
+		maxWorkers,
+		generator.DefaultWorkers,
+	)
+
+	rng, err := newRange(newRangePoint(generator.DefaultWorkers, true), newRangePoint(maxWorkers, true))
 	if err != nil {
-		return nil, fmt.Errorf("getting end coord: %w", err)
+		return 0, fmt.Errorf("create range: %w", err)
 	}
 
-	genAlgo, err := p.generationAlgorithm(scan)
+	for {
+		if !scan.Scan() {
+			return 0, ErrNoInputLines{}
+		}
+
+		inputLine := strings.TrimSpace(scan.Text())
+		if inputLine == "" {
+			return generator.DefaultWorkers, nil
+		}
+
+		workers, err := strconv.Atoi(inputLine)
+
+		switch {
+		case err != nil:
+			// ANSI code for red letters
+			fmt.Fprintf(p.out, "\033[31mError: %s.\033[0m\nType a single integer: ", err)
+		case !rng.Contains(workers):
+			// ANSI code for red letters
+			fmt.Fprintf(p.out, "\033[31mError: Integer should be in range %s.\033[0m\nType a valid integer: ", rng)
+		default:
+			return workers, nil
+		}
+	}
+}
+
+func (p *Presentation) merge(scan *bufio.Scanner) (generator.MergeStrategy, error) {
+	fmt.Fprintln(p.out, "Choose how cells carved by several partial mazes are merged:")
+
+	name, err := p.menu(scan, p.merges)
+	if err != nil {
+		return 0, fmt.Errorf("p.menu(scan, %v): %w", p.merges, err)
+	}
+
+	for _, merge := range []generator.MergeStrategy{
+		generator.MergeRandom,
+		generator.MergeCheapest,
+		generator.MergeStartSide,
+		generator.MergeSeam,
+		generator.MergeInterleave,
+	} {
+		if merge.String() == name {
+			return merge, nil
+		}
+	}
+
+	return 0, NewErrUnknownMerge(name)
+}
+
+func (p *Presentation) topology(scan *bufio.Scanner) (domain.Topology, error) {
+	fmt.Fprintln(p.out, "Choose grid topology:")
+
+	name, err := p.menu(scan, p.topologies)
+	if err != nil {
+		return 0, fmt.Errorf("p.menu(scan, %v): %w", p.topologies, err)
+	}
+
+	for _, topology := range []domain.Topology{domain.Square, domain.Hex, domain.Polar, domain.Torus} {
+		if topology.String() == name {
+			return topology, nil
+		}
+	}
+
+	return 0, NewErrUnknownTopology(name)
+}
+
+func (p *Presentation) mask(scan *bufio.Scanner) (domain.Mask, error) {
+	fmt.Fprint(p.out, "Enter mask file, a .png image or a text file (leave empty for a rectangular maze): ")
+
+	for {
+		if !scan.Scan() {
+			return nil, ErrNoInputLines{}
+		}
+
+		path := strings.TrimSpace(scan.Text())
+		if path == "" {
+			return nil, nil
+		}
+
+		msk, err := mask.Load(path)
+		if err != nil {
+			// ANSI code for red letters
+			fmt.Fprintf(p.out, "\033[31mError: %s.\033[0m\nType a valid mask file or leave it empty: ", err)
+			continue
+		}
+
+		return msk, nil
+	}
+}
+
+// mazeData reads the mask, dimensions, levels, start and end points of a maze
+// on a square, hex or torus grid. Only square mazes can be masked, the mask
+// sets their dimensions.
+func (p *Presentation) mazeData(scan *bufio.Scanner, topology domain.Topology) (domain.MazeData, error) {
+	var (
+		msk domain.Mask
+		dim dimension
+		err error
+	)
+
+	if topology == domain.Square {
+		msk, err = p.mask(scan)
+		if err != nil {
+			return domain.MazeData{}, fmt.Errorf("getting mask: %w", err)
+		}
+	}
+
+	if msk != nil {
+		dim = dimension{height: msk.Height(), width: msk.Width()}
+	} else {
+		dim, err = p.mazeDimension(scan)
+		if err != nil {
+			return domain.MazeData{}, fmt.Errorf("getting dimension: %w", err)
+		}
+	}
+
+	levels, err := p.levels(scan)
+	if err != nil {
+		return domain.MazeData{}, fmt.Errorf("getting levels: %w", err)
+	}
+
+	data := domain.NewLevelMazeData(levels, dim.height, dim.width, domain.Coord{}, domain.Coord{})
+	data.Topology = topology
+	data.Mask = msk
+
+	start, err := p.startCoord(scan, data)
+	if err != nil {
+		return domain.MazeData{}, fmt.Errorf("getting start coord: %w", err)
+	}
+
+	end, err := p.endCoord(scan, data, start)
+	if err != nil {
+		return domain.MazeData{}, fmt.Errorf("getting end coord: %w", err)
+	}
+
+	data.Start, data.End = start, domain.NewLevelCoord(levels-1, end.Row, end.Col)
+
+	return data, nil
+}
+
+// gridData reads the topology and the maze data of the grid: the number of
+// rings of polar mazes and the dimensions, start and end points of others.
+func (p *Presentation) gridData(scan *bufio.Scanner) (domain.MazeData, error) {
+	topology, err := p.topology(scan)
+	if err != nil {
+		return domain.MazeData{}, fmt.Errorf("getting topology: %w", err)
+	}
+
+	var data domain.MazeData
+
+	if topology == domain.Polar {
+		rings, err := p.rings(scan)
+		if err != nil {
+			return domain.MazeData{}, fmt.Errorf("getting rings: %w", err)
+		}
+
+		data = domain.NewPolarMazeData(rings)
+	} else {
+		data, err = p.mazeData(scan, topology)
+		if err != nil {
+			return domain.MazeData{}, fmt.Errorf("getting maze data: %w", err)
+		}
+	}
+
+	data.Topology = topology
+
+	return data, nil
+}
+
+// generationSettings reads the settings of the generator which don't depend
+// on the algorithm.
+func (p *Presentation) generationSettings(scan *bufio.Scanner) (settings, error) {
+	seed, err := p.seed(scan)
+	if err != nil {
+		return settings{}, fmt.Errorf("getting seed: %w", err)
+	}
+
+	terrain, err := p.terrain(scan)
+	if err != nil {
+		return settings{}, fmt.Errorf("getting terrain: %w", err)
+	}
+
+	biomes, err := p.biomes(scan)
+	if err != nil {
+		return settings{}, fmt.Errorf("getting terrain layout: %w", err)
+	}
+
+	braid, err := p.braid(scan)
+	if err != nil {
+		return settings{}, fmt.Errorf("getting braid: %w", err)
+	}
+
+	difficulty, err := p.difficulty(scan)
+	if err != nil {
+		return settings{}, fmt.Errorf("getting difficulty: %w", err)
+	}
+
+	workers, err := p.workers(scan)
+	if err != nil {
+		return settings{}, fmt.Errorf("getting workers: %w", err)
+	}
+
+	merge, err := p.merge(scan)
+	if err != nil {
+		return settings{}, fmt.Errorf("getting merge strategy: %w", err)
+	}
+
+	return settings{
+		seed:       seed,
+		terrain:    terrain,
+		biomes:     biomes,
+		braid:      braid,
+		difficulty: difficulty,
+		workers:    workers,
+		merge:      merge,
+	}, nil
+}
+
+func (p *Presentation) ProcessInput() (*Input, error) {
+	fmt.Fprint(p.out, greetingMessage)
+
+	scan := p.scan
+
+	data, err := p.gridData(scan)
+	if err != nil {
+		return nil, err
+	}
+
+	genAlgo, err := p.generationAlgorithm(scan, data)
 	if err != nil {
 		return nil, fmt.Errorf("getting generation algorithm: %w", err)
 	}
 
-	pathFindAlgo, err := p.pathFinderAlgorithm(scan)
+	genParams, err := p.algorithmParams(scan, genAlgo.Params)
+	if err != nil {
+		return nil, fmt.Errorf("getting generation algorithm params: %w", err)
+	}
+
+	pathFindAlgo, err := p.pathFinderAlgorithm(scan, data)
 	if err != nil {
 		return nil, fmt.Errorf("getting path finder algorithm: %w", err)
 	}
 
-	return NewInput(dim.height, dim.width, start, end, genAlgo, pathFindAlgo), nil
+	pathFindParams, err := p.algorithmParams(scan, pathFindAlgo.Params)
+	if err != nil {
+		return nil, fmt.Errorf("getting path finder algorithm params: %w", err)
+	}
+
+	genSettings, err := p.generationSettings(scan)
+	if err != nil {
+		return nil, err
+	}
+
+	p.writeCellsInfo(genSettings.terrain, data.Levels)
+	fmt.Print("Enjoy the program!\n\n")
+
+	input := NewInput(
+		data.Height,
+		data.Width,
+		data.Start,
+		data.End,
+		genAlgo.Name,
+		pathFindAlgo.Name,
+		genSettings.seed,
+		genSettings.terrain,
+		genSettings.biomes,
+		genSettings.braid,
+	)
+	input.GenParams = genParams
+	input.PathFindParams = pathFindParams
+	input.Topology = data.Topology
+	input.Levels = data.Levels
+	input.Mask = data.Mask
+	input.Difficulty = genSettings.difficulty
+	input.Workers = genSettings.workers
+	input.Merge = genSettings.merge
+
+	return input, nil
+}
+
+// NextFloor asks which floor of the maze to show after the current one. It
+// returns false when the user wants to stop watching the maze.
+func (p *Presentation) NextFloor(floor, floors int) (int, bool, error) {
+	fmt.Fprintf(
+		p.out,
+		"Floor %d of %d. Type n for the next floor, p for the previous one or leave empty to exit: ",
+		floor+1,
+		floors,
+	)
+
+	for {
+		if !p.scan.Scan() {
+			return 0, false, ErrNoInputLines{}
+		}
+
+		switch strings.TrimSpace(p.scan.Text()) {
+		case "":
+			return floor, false, nil
+		case "n":
+			return min(floor+1, floors-1), true, nil
+		case "p":
+			return max(floor-1, 0), true, nil
+		default:
+			// ANSI code for red letters
+			fmt.Fprint(p.out, "\033[31mError: Unknown command.\033[0m\nType n, p or leave empty: ")
+		}
+	}
 }
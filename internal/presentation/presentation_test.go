@@ -4,9 +4,14 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"os"
+	"path/filepath"
+	"strconv"
 	"testing"
 
 	"github.com/LLIEPJIOK/mazegenerator/internal/domain"
+	"github.com/LLIEPJIOK/mazegenerator/internal/generator"
+	"github.com/LLIEPJIOK/mazegenerator/internal/mask"
 	"github.com/LLIEPJIOK/mazegenerator/internal/presentation"
 	"github.com/stretchr/testify/require"
 )
@@ -19,7 +24,7 @@ func TestProcessInput(t *testing.T) {
 		expected *presentation.Input
 	}{
 		{
-			input: "10\n10\n0\n0\n9\n9\n1\n1",
+			input: "1\n\n10\n10\n1\n0\n0\n9\n9\n1\n1\n11\n1\n1\n\n\n\n1\n",
 			expected: presentation.NewInput(
 				10,
 				10,
@@ -27,10 +32,14 @@ func TestProcessInput(t *testing.T) {
 				domain.NewCoord(9, 9),
 				"prim",
 				"dijkstra",
+				11,
+				terrainPreset("default"),
+				false,
+				generator.DefaultBraid,
 			),
 		},
 		{
-			input: "15\n15\n2\n0\n14\n14\n1\n2",
+			input: "1\n\n15\n15\n1\n2\n0\n14\n14\n1\n2\n22\n2\n2\n0\n\n\n1\n",
 			expected: presentation.NewInput(
 				15,
 				15,
@@ -38,10 +47,14 @@ func TestProcessInput(t *testing.T) {
 				domain.NewCoord(14, 14),
 				"prim",
 				"a-star",
+				22,
+				terrainPreset("desert"),
+				true,
+				0,
 			),
 		},
 		{
-			input: "20\n20\n5\n19\n19\n2\n2\n1",
+			input: "1\n\n20\n20\n1\n5\n19\n19\n2\n2\n1\n33\n3\n1\n1\n\n\n1\n",
 			expected: presentation.NewInput(
 				20,
 				20,
@@ -49,10 +62,14 @@ func TestProcessInput(t *testing.T) {
 				domain.NewCoord(19, 2),
 				"backtrack",
 				"dijkstra",
+				33,
+				terrainPreset("swamp"),
+				false,
+				1,
 			),
 		},
 		{
-			input: "12\n12\n0\n11\n11\n0\n2\n2",
+			input: "1\n\n12\n12\n1\n0\n11\n11\n0\n2\n2\n44\n4\n1\n0.25\n\n\n1\n",
 			expected: presentation.NewInput(
 				12,
 				12,
@@ -60,10 +77,14 @@ func TestProcessInput(t *testing.T) {
 				domain.NewCoord(11, 0),
 				"backtrack",
 				"a-star",
+				44,
+				terrainPreset("treasure-hunt"),
+				false,
+				0.25,
 			),
 		},
 		{
-			input: "30\n30\n0\n15\n29\n18\n1\n1",
+			input: "1\n\n30\n30\n1\n0\n15\n29\n18\n1\n1\n55\n1\n2\n\n\n\n1\n",
 			expected: presentation.NewInput(
 				30,
 				30,
@@ -71,10 +92,14 @@ func TestProcessInput(t *testing.T) {
 				domain.NewCoord(29, 18),
 				"prim",
 				"dijkstra",
+				55,
+				terrainPreset("default"),
+				true,
+				generator.DefaultBraid,
 			),
 		},
 		{
-			input: "25\n25\n10\n24\n24\n24\n1\n2",
+			input: "1\n\n25\n25\n1\n10\n24\n24\n24\n1\n2\n66\n2\n1\n0\n\n\n1\n",
 			expected: presentation.NewInput(
 				25,
 				25,
@@ -82,10 +107,14 @@ func TestProcessInput(t *testing.T) {
 				domain.NewCoord(24, 24),
 				"prim",
 				"a-star",
+				66,
+				terrainPreset("desert"),
+				false,
+				0,
 			),
 		},
 		{
-			input: "18\n18\n3\n0\n0\n17\n2\n1",
+			input: "1\n\n18\n18\n1\n3\n0\n0\n17\n2\n1\n77\n3\n1\n1\n\n\n1\n",
 			expected: presentation.NewInput(
 				18,
 				18,
@@ -93,21 +122,29 @@ func TestProcessInput(t *testing.T) {
 				domain.NewCoord(0, 17),
 				"backtrack",
 				"dijkstra",
+				77,
+				terrainPreset("swamp"),
+				false,
+				1,
 			),
 		},
 		{
-			input: "8\n8\n0\n0\n7\n7\n2\n2",
-			expected: presentation.NewInput(
+			input: "2\n8\n8\n1\n0\n0\n7\n7\n2\n2\n88\n4\n2\n0.25\n\n\n1\n",
+			expected: hexInput(presentation.NewInput(
 				8,
 				8,
 				domain.NewCoord(0, 0),
 				domain.NewCoord(7, 7),
 				"backtrack",
 				"a-star",
-			),
+				88,
+				terrainPreset("treasure-hunt"),
+				true,
+				0.25,
+			)),
 		},
 		{
-			input: "50\n50\n25\n0\n49\n49\n1\n1",
+			input: "1\n\n50\n50\n1\n25\n0\n49\n49\n1\n1\n99\n1\n1\n\n\n\n1\n",
 			expected: presentation.NewInput(
 				50,
 				50,
@@ -115,10 +152,14 @@ func TestProcessInput(t *testing.T) {
 				domain.NewCoord(49, 49),
 				"prim",
 				"dijkstra",
+				99,
+				terrainPreset("default"),
+				false,
+				generator.DefaultBraid,
 			),
 		},
 		{
-			input: "40\n40\n20\n39\n39\n39\n1\n2",
+			input: "1\n\n40\n40\n1\n20\n39\n39\n39\n1\n2\n110\n2\n1\n0\n\n\n1\n",
 			expected: presentation.NewInput(
 				40,
 				40,
@@ -126,10 +167,14 @@ func TestProcessInput(t *testing.T) {
 				domain.NewCoord(39, 39),
 				"prim",
 				"a-star",
+				110,
+				terrainPreset("desert"),
+				false,
+				0,
 			),
 		},
 		{
-			input: "5\n5\n0\n1\n4\n4\n2\n1",
+			input: "1\n\n5\n5\n1\n0\n1\n4\n4\n2\n1\n121\n3\n2\n1\n\n\n1\n",
 			expected: presentation.NewInput(
 				5,
 				5,
@@ -137,8 +182,141 @@ func TestProcessInput(t *testing.T) {
 				domain.NewCoord(4, 4),
 				"backtrack",
 				"dijkstra",
+				121,
+				terrainPreset("swamp"),
+				true,
+				1,
 			),
 		},
+		{
+			input: "1\n\n9\n9\n1\n0\n4\n8\n4\n9\n75% newest, 25% random\n1\n1\n4\n1\n0.25\n\n\n1\n",
+			expected: growingTreeInput(
+				9,
+				9,
+				domain.NewCoord(0, 4),
+				domain.NewCoord(8, 4),
+				"75% newest, 25% random",
+				"dijkstra",
+				1,
+				terrainPreset("treasure-hunt"),
+				false,
+				0.25,
+			),
+		},
+		{
+			input: "2\n12\n12\n1\n0\n0\n11\n11\n6\n2\n2\n8\n1\n1\n\n\n\n1\n",
+			expected: hexInput(divisionInput(
+				12,
+				12,
+				domain.NewCoord(0, 0),
+				domain.NewCoord(11, 11),
+				2,
+				"a-star",
+				8,
+				terrainPreset("default"),
+				false,
+				generator.DefaultBraid,
+			)),
+		},
+		{
+			input:    "3\n5\n2\n2\n9\n1\n1\n0.5\n\n\n1\n",
+			expected: polarInput(5, "backtrack", "a-star", 9, terrainPreset("default"), false, 0.5),
+		},
+		{
+			input: "1\n\n10\n10\n3\n0\n0\n9\n9\n1\n2\n12\n1\n1\n\n\n\n1\n",
+			expected: levelInput(3, presentation.NewInput(
+				10,
+				10,
+				domain.NewCoord(0, 0),
+				domain.NewCoord(9, 9),
+				"prim",
+				"a-star",
+				12,
+				terrainPreset("default"),
+				false,
+				generator.DefaultBraid,
+			)),
+		},
+		{
+			input: "4\n10\n12\n1\n0\n0\n9\n11\n3\n1\n7\n1\n1\n\n\n\n1\n",
+			expected: torusInput(presentation.NewInput(
+				10,
+				12,
+				domain.NewCoord(0, 0),
+				domain.NewCoord(9, 11),
+				"kruskal",
+				"dijkstra",
+				7,
+				terrainPreset("default"),
+				false,
+				generator.DefaultBraid,
+			)),
+		},
+		{
+			input: "1\n\n10\n10\n1\n0\n0\n9\n9\n2\n1\n4\n1\n1\n\ncost 60, decisions 5\n\n1\n",
+			expected: difficultyInput(generator.Difficulty{PathCost: 60, Decisions: 5}, presentation.NewInput(
+				10,
+				10,
+				domain.NewCoord(0, 0),
+				domain.NewCoord(9, 9),
+				"backtrack",
+				"dijkstra",
+				4,
+				terrainPreset("default"),
+				false,
+				generator.DefaultBraid,
+			)),
+		},
+		{
+			input: "1\n\n20\n30\n1\n0\n0\n19\n29\n10\n\n5\n1\n3\n1\n1\n\n\n\n1\n",
+			expected: paramsInput(map[string]string{"min-room-size": "3", "max-room-size": "5"}, presentation.NewInput(
+				20,
+				30,
+				domain.NewCoord(0, 0),
+				domain.NewCoord(19, 29),
+				"dungeon",
+				"dijkstra",
+				3,
+				terrainPreset("default"),
+				false,
+				generator.DefaultBraid,
+			)),
+		},
+		{
+			input: "4\n16\n16\n1\n0\n0\n15\n15\n8\n0.5\n\n\n\n2\n5\n2\n1\n\n\n\n1\n",
+			expected: paramsInput(map[string]string{
+				"fill":       "0.5",
+				"iterations": "4",
+				"birth":      "5",
+				"survival":   "4",
+			}, torusInput(presentation.NewInput(
+				16,
+				16,
+				domain.NewCoord(0, 0),
+				domain.NewCoord(15, 15),
+				"cave",
+				"a-star",
+				5,
+				terrainPreset("desert"),
+				false,
+				generator.DefaultBraid,
+			))),
+		},
+		{
+			input: "1\n\n10\n10\n1\n0\n0\n9\n9\n1\n1\n11\n1\n1\n\n\n4\n4\n",
+			expected: workersInput(4, generator.MergeSeam, presentation.NewInput(
+				10,
+				10,
+				domain.NewCoord(0, 0),
+				domain.NewCoord(9, 9),
+				"prim",
+				"dijkstra",
+				11,
+				terrainPreset("default"),
+				false,
+				generator.DefaultBraid,
+			)),
+		},
 	}
 
 	for i, testCase := range testCases {
@@ -155,6 +333,82 @@ func TestProcessInput(t *testing.T) {
 	}
 }
 
+func terrainPreset(name string) domain.TerrainProfile {
+	terrain, _ := domain.TerrainPreset(name)
+
+	return terrain
+}
+
+func divisionInput(height, width int, start, end domain.Coord, chamberSize int, pathFindAlgo string,
+	seed uint64, terrain domain.TerrainProfile, biomes bool, braid float64,
+) *presentation.Input {
+	input := presentation.NewInput(height, width, start, end, "division", pathFindAlgo, seed, terrain, biomes, braid)
+	input.GenParams = map[string]string{"chamber-size": strconv.Itoa(chamberSize)}
+
+	return input
+}
+
+func growingTreeInput(height, width int, start, end domain.Coord, strategy string,
+	pathFindAlgo string, seed uint64, terrain domain.TerrainProfile, biomes bool, braid float64,
+) *presentation.Input {
+	input := presentation.NewInput(
+		height, width, start, end, "growing-tree", pathFindAlgo, seed, terrain, biomes, braid,
+	)
+	input.GenParams = map[string]string{"strategy": strategy}
+
+	return input
+}
+
+func paramsInput(params map[string]string, input *presentation.Input) *presentation.Input {
+	input.GenParams = params
+
+	return input
+}
+
+func polarInput(rings int, genAlgo, pathFindAlgo string, seed uint64, terrain domain.TerrainProfile, biomes bool,
+	braid float64,
+) *presentation.Input {
+	data := domain.NewPolarMazeData(rings)
+	input := presentation.NewInput(
+		data.Height, data.Width, data.Start, data.End, genAlgo, pathFindAlgo, seed, terrain, biomes, braid,
+	)
+	input.Topology = domain.Polar
+
+	return input
+}
+
+func hexInput(input *presentation.Input) *presentation.Input {
+	input.Topology = domain.Hex
+
+	return input
+}
+
+func torusInput(input *presentation.Input) *presentation.Input {
+	input.Topology = domain.Torus
+
+	return input
+}
+
+func levelInput(levels int, input *presentation.Input) *presentation.Input {
+	input.Levels = levels
+	input.End.Level = levels - 1
+
+	return input
+}
+
+func difficultyInput(difficulty generator.Difficulty, input *presentation.Input) *presentation.Input {
+	input.Difficulty = difficulty
+
+	return input
+}
+
+func workersInput(workers int, merge generator.MergeStrategy, input *presentation.Input) *presentation.Input {
+	input.Workers = workers
+	input.Merge = merge
+
+	return input
+}
+
 func TestProcessInputWithInvalidData(t *testing.T) {
 	t.Parallel()
 
@@ -163,7 +417,7 @@ func TestProcessInputWithInvalidData(t *testing.T) {
 		expected *presentation.Input
 	}{
 		{
-			input: "10\n10\n-1\n0\n0\n9\n9\n1\n1",
+			input: "1\n\n10\n10\n1\n-1\n0\n0\n9\n9\n1\n1\n132\n2\n2\n0\n\n\n1\n",
 			expected: presentation.NewInput(
 				10,
 				10,
@@ -171,10 +425,14 @@ func TestProcessInputWithInvalidData(t *testing.T) {
 				domain.NewCoord(9, 9),
 				"prim",
 				"dijkstra",
+				132,
+				terrainPreset("desert"),
+				true,
+				0,
 			),
 		},
 		{
-			input: "15\n15\n55\n2\n0\n14\n14\n1\n2",
+			input: "1\n\n15\n15\n1\n55\n2\n0\n14\n14\n1\n2\n143\n3\n1\n1\n\n\n1\n",
 			expected: presentation.NewInput(
 				15,
 				15,
@@ -182,10 +440,14 @@ func TestProcessInputWithInvalidData(t *testing.T) {
 				domain.NewCoord(14, 14),
 				"prim",
 				"a-star",
+				143,
+				terrainPreset("swamp"),
+				false,
+				1,
 			),
 		},
 		{
-			input: "20\n20\n5\n19\n19\n2\n2\n30\n1",
+			input: "1\n\n20\n20\n1\n5\n19\n19\n2\n2\n30\n1\n154\n4\n1\n0.25\n\n\n1\n",
 			expected: presentation.NewInput(
 				20,
 				20,
@@ -193,10 +455,14 @@ func TestProcessInputWithInvalidData(t *testing.T) {
 				domain.NewCoord(19, 2),
 				"backtrack",
 				"dijkstra",
+				154,
+				terrainPreset("treasure-hunt"),
+				false,
+				0.25,
 			),
 		},
 		{
-			input: "12\n12\n0\n11\n0\n11\n11\n0\n2\n2",
+			input: "1\n\n12\n12\n1\n0\n11\n0\n11\n11\n0\n2\n2\n165\n1\n2\n\n\n\n1\n",
 			expected: presentation.NewInput(
 				12,
 				12,
@@ -204,10 +470,14 @@ func TestProcessInputWithInvalidData(t *testing.T) {
 				domain.NewCoord(11, 0),
 				"backtrack",
 				"a-star",
+				165,
+				terrainPreset("default"),
+				true,
+				generator.DefaultBraid,
 			),
 		},
 		{
-			input: "30\n30\n4\n4\n0\n15\n29\n18\n1\n1",
+			input: "1\n\n30\n30\n1\n4\n4\n0\n15\n29\n18\n1\n1\n176\n2\n1\n0\n\n\n1\n",
 			expected: presentation.NewInput(
 				30,
 				30,
@@ -215,8 +485,151 @@ func TestProcessInputWithInvalidData(t *testing.T) {
 				domain.NewCoord(29, 18),
 				"prim",
 				"dijkstra",
+				176,
+				terrainPreset("desert"),
+				false,
+				0,
 			),
 		},
+		{
+			input: "1\n\n10\n10\n1\n0\n0\n9\n9\n9\nnewest oldest\n\n2\n4\n3\n1\n1\n\n\n1\n",
+			expected: growingTreeInput(
+				10,
+				10,
+				domain.NewCoord(0, 0),
+				domain.NewCoord(9, 9),
+				generator.DefaultStrategy,
+				"a-star",
+				4,
+				terrainPreset("swamp"),
+				false,
+				1,
+			),
+		},
+		{
+			input: "1\n\n10\n10\n1\n0\n0\n9\n9\n6\n0\n3\n1\n5\n4\n2\n0.25\n\n\n1\n",
+			expected: divisionInput(
+				10,
+				10,
+				domain.NewCoord(0, 0),
+				domain.NewCoord(9, 9),
+				3,
+				"dijkstra",
+				5,
+				terrainPreset("treasure-hunt"),
+				true,
+				0.25,
+			),
+		},
+		{
+			input: "1\n\n10\n10\n1\n0\n0\n9\n9\n1\n2\n7\n1\n1\n1.5\nhalf\n-0.1\n0.75\n\n\n1\n",
+			expected: presentation.NewInput(
+				10,
+				10,
+				domain.NewCoord(0, 0),
+				domain.NewCoord(9, 9),
+				"prim",
+				"a-star",
+				7,
+				terrainPreset("default"),
+				false,
+				0.75,
+			),
+		},
+		{
+			input: "0\n5\n2\n10\n10\n1\n0\n0\n9\n9\n2\n2\nseed\n-5\n123\n1\n1\n\n\n\n1\n",
+			expected: hexInput(presentation.NewInput(
+				10,
+				10,
+				domain.NewCoord(0, 0),
+				domain.NewCoord(9, 9),
+				"backtrack",
+				"a-star",
+				123,
+				terrainPreset("default"),
+				false,
+				generator.DefaultBraid,
+			)),
+		},
+		{
+			input:    "3\n1\n12\n3\n1\n1\n3\n3\n2\n\n\n\n1\n",
+			expected: polarInput(12, "prim", "dijkstra", 3, terrainPreset("swamp"), true, generator.DefaultBraid),
+		},
+		{
+			input: "2\n8\n8\n0\n2\n0\n0\n7\n7\n3\n2\n1\n5\n2\n1\n0\n\n\n1\n",
+			expected: levelInput(2, hexInput(presentation.NewInput(
+				8,
+				8,
+				domain.NewCoord(0, 0),
+				domain.NewCoord(7, 7),
+				"backtrack",
+				"dijkstra",
+				5,
+				terrainPreset("desert"),
+				false,
+				0,
+			))),
+		},
+		{
+			input: "4\n9\n9\n1\n0\n0\n8\n8\n4\n2\n1\n8\n1\n1\n0\n\n\n1\n",
+			expected: torusInput(presentation.NewInput(
+				9,
+				9,
+				domain.NewCoord(0, 0),
+				domain.NewCoord(8, 8),
+				"backtrack",
+				"dijkstra",
+				8,
+				terrainPreset("default"),
+				false,
+				0,
+			)),
+		},
+		{
+			input: "1\n\n20\n20\n1\n0\n0\n19\n19\n10\nbig\n0\n\n6\n2\n7\n1\n1\n\n\n\n1\n",
+			expected: paramsInput(map[string]string{"min-room-size": "3", "max-room-size": "6"}, presentation.NewInput(
+				20,
+				20,
+				domain.NewCoord(0, 0),
+				domain.NewCoord(19, 19),
+				"dungeon",
+				"a-star",
+				7,
+				terrainPreset("default"),
+				false,
+				generator.DefaultBraid,
+			)),
+		},
+		{
+			input: "1\n\n10\n10\n1\n0\n0\n9\n9\n1\n1\n9\n1\n1\n\ncost\ndead-ends 2\nhardness 5\ndead-ends 0.1\n\n1\n",
+			expected: difficultyInput(generator.Difficulty{DeadEnds: 0.1}, presentation.NewInput(
+				10,
+				10,
+				domain.NewCoord(0, 0),
+				domain.NewCoord(9, 9),
+				"prim",
+				"dijkstra",
+				9,
+				terrainPreset("default"),
+				false,
+				generator.DefaultBraid,
+			)),
+		},
+		{
+			input: "1\n\n10\n10\n1\n0\n0\n9\n9\n2\n2\n9\n1\n1\n\n\n1\n17\nmany\n16\n0\n6\n5\n",
+			expected: workersInput(16, generator.MergeInterleave, presentation.NewInput(
+				10,
+				10,
+				domain.NewCoord(0, 0),
+				domain.NewCoord(9, 9),
+				"backtrack",
+				"a-star",
+				9,
+				terrainPreset("default"),
+				false,
+				generator.DefaultBraid,
+			)),
+		},
 	}
 
 	for i, testCase := range testCases {
@@ -233,6 +646,76 @@ func TestProcessInputWithInvalidData(t *testing.T) {
 	}
 }
 
+func TestProcessInputWithMask(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "diamond.txt")
+	require.NoError(t, os.WriteFile(path, []byte("..###..\n.#####.\n#######\n.#####.\n..###..\n"), 0o600))
+
+	msk, err := mask.Load(path)
+	require.NoError(t, err, "mask should be loaded without error")
+
+	// the missing mask, the start outside the mask and the end inside it are rejected
+	input := fmt.Sprintf(
+		"1\n%s\n%s\n1\n0\n0\n0\n2\n2\n3\n1\n5\n1\n1\n11\n1\n1\n\n\n\n1\n",
+		filepath.Join(t.TempDir(), "missing.txt"),
+		path,
+	)
+
+	expected := presentation.NewInput(
+		5,
+		7,
+		domain.NewCoord(0, 2),
+		domain.NewCoord(1, 5),
+		"prim",
+		"dijkstra",
+		11,
+		terrainPreset("default"),
+		false,
+		generator.DefaultBraid,
+	)
+	expected.Mask = msk
+
+	pres := presentation.New(bytes.NewBufferString(input), io.Discard)
+	got, err := pres.ProcessInput()
+
+	require.NoError(t, err, "input should get without error")
+	require.Equal(t, expected, got, "values should be equal")
+}
+
+func TestProcessInputWithIslandMask(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "islands.txt")
+	require.NoError(t, os.WriteFile(path, []byte("##.##\n##.##\n##.##\n##.##\n##.##\n"), 0o600))
+
+	msk, err := mask.Load(path)
+	require.NoError(t, err, "mask should be loaded without error")
+
+	// the end on the other island is rejected
+	input := fmt.Sprintf("1\n%s\n1\n0\n0\n0\n4\n4\n1\n1\n1\n5\n1\n1\n\n\n\n1\n", path)
+
+	expected := presentation.NewInput(
+		5,
+		5,
+		domain.NewCoord(0, 0),
+		domain.NewCoord(4, 1),
+		"prim",
+		"dijkstra",
+		5,
+		terrainPreset("default"),
+		false,
+		generator.DefaultBraid,
+	)
+	expected.Mask = msk
+
+	pres := presentation.New(bytes.NewBufferString(input), io.Discard)
+	got, err := pres.ProcessInput()
+
+	require.NoError(t, err, "input should get without error")
+	require.Equal(t, expected, got, "values should be equal")
+}
+
 func TestProcessInputWithError(t *testing.T) {
 	t.Parallel()
 
@@ -243,28 +726,61 @@ func TestProcessInputWithError(t *testing.T) {
 			input: "",
 		},
 		{
-			input: "18\n18",
+			input: "1\n\n18\n18\n1",
+		},
+		{
+			input: "1\n\n8\n8\n1\n0\n0",
+		},
+		{
+			input: "1\n\n50\n50\n1\n25\n0\n49",
+		},
+		{
+			input: "1\n\n40\n40\n1\n20\n39\n39\n39\n",
+		},
+		{
+			input: "1\n\n5\n5\n1\n0\n1\n4\n4\n2\n",
+		},
+		{
+			input: "1\n\n5 0",
+		},
+		{
+			input: "1\n\n5\n0",
+		},
+		{
+			input: "1\n\n5\n5\n1\n0\n0\n3\n3",
+		},
+		{
+			input: "1\n\n10\n10\n1\n0\n0\n9\n9\n1\n1",
+		},
+		{
+			input: "1\n\n10\n10\n1\n0\n0\n9\n9\n6\n",
+		},
+		{
+			input: "1\n\n10\n10\n1\n0\n0\n9\n9\n1\n1\n7",
+		},
+		{
+			input: "1\n\n10\n10\n1\n0\n0\n9\n9\n1\n1\n7\n5",
 		},
 		{
-			input: "8\n8\n0\n0",
+			input: "1\n\n10\n10\n1\n0\n0\n9\n9\n1\n1\n7\n1\n3",
 		},
 		{
-			input: "50\n50\n25\n0\n49",
+			input: "3\n6\n3",
 		},
 		{
-			input: "40\n40\n20\n39\n39\n39\n",
+			input: "3\n1",
 		},
 		{
-			input: "5\n5\n0\n1\n4\n4\n2\n",
+			input: "1\n\n10\n10\n0",
 		},
 		{
-			input: "5 0",
+			input: "1\n\n10\n10\n1\n0\n0\n9\n9\n1\n1\n7\n1\n1\n0",
 		},
 		{
-			input: "5\n0",
+			input: "1\n\n10\n10\n1\n0\n0\n9\n9\n1\n1\n7\n1\n1\n0\n\n",
 		},
 		{
-			input: "5\n5\n0\n0\n3\n3",
+			input: "1\n\n10\n10\n1\n0\n0\n9\n9\n1\n1\n7\n1\n1\n0\n\n3\n",
 		},
 	}
 
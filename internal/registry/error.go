@@ -0,0 +1,81 @@
+package registry
+
+import "fmt"
+
+type ErrUnknownAlgorithm struct {
+	name string
+}
+
+func NewErrUnknownAlgorithm(name string) ErrUnknownAlgorithm {
+	return ErrUnknownAlgorithm{
+		name: name,
+	}
+}
+
+func (e ErrUnknownAlgorithm) Error() string {
+	return fmt.Sprintf("unknown algorithm %q", e.name)
+}
+
+type ErrDuplicateAlgorithm struct {
+	name string
+}
+
+func NewErrDuplicateAlgorithm(name string) ErrDuplicateAlgorithm {
+	return ErrDuplicateAlgorithm{
+		name: name,
+	}
+}
+
+func (e ErrDuplicateAlgorithm) Error() string {
+	return fmt.Sprintf("algorithm %q is already registered", e.name)
+}
+
+type ErrUnknownParam struct {
+	algorithm string
+	param     string
+}
+
+func NewErrUnknownParam(algorithm, param string) ErrUnknownParam {
+	return ErrUnknownParam{
+		algorithm: algorithm,
+		param:     param,
+	}
+}
+
+func (e ErrUnknownParam) Error() string {
+	return fmt.Sprintf("algorithm %q has no parameter %q", e.algorithm, e.param)
+}
+
+type ErrInvalidInt struct {
+	value string
+	mn    int
+}
+
+func NewErrInvalidInt(value string, mn int) ErrInvalidInt {
+	return ErrInvalidInt{
+		value: value,
+		mn:    mn,
+	}
+}
+
+func (e ErrInvalidInt) Error() string {
+	return fmt.Sprintf("%q isn't an integer not less than %d", e.value, e.mn)
+}
+
+type ErrInvalidFloat struct {
+	value string
+	mn    float64
+	mx    float64
+}
+
+func NewErrInvalidFloat(value string, mn, mx float64) ErrInvalidFloat {
+	return ErrInvalidFloat{
+		value: value,
+		mn:    mn,
+		mx:    mx,
+	}
+}
+
+func (e ErrInvalidFloat) Error() string {
+	return fmt.Sprintf("%q isn't a number in range [%g, %g]", e.value, e.mn, e.mx)
+}
@@ -0,0 +1,172 @@
+package registry
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/LLIEPJIOK/mazegenerator/internal/domain"
+)
+
+// Values holds parsed parameters of an algorithm by their names.
+type Values map[string]any
+
+// Int returns the integer parameter or 0 if it isn't an integer.
+func (v Values) Int(name string) int {
+	value, _ := v[name].(int)
+
+	return value
+}
+
+// Float returns the number parameter or 0 if it isn't a number.
+func (v Values) Float(name string) float64 {
+	value, _ := v[name].(float64)
+
+	return value
+}
+
+// Param describes a parameter of an algorithm, so menus, command lines and
+// other interfaces can ask for it without knowing the algorithm.
+type Param struct {
+	Name        string
+	Description string
+	// Default is used when the value is empty. A parameter without a
+	// default must be set.
+	Default string
+	// Parse validates the value and converts it to the type the algorithm
+	// is created with.
+	Parse func(value string) (any, error)
+}
+
+func NewParam(name, description, def string, parse func(value string) (any, error)) Param {
+	return Param{
+		Name:        name,
+		Description: description,
+		Default:     def,
+		Parse:       parse,
+	}
+}
+
+// IntParam returns a parameter holding an integer not less than mn.
+func IntParam(name, description string, def, mn int) Param {
+	return NewParam(name, description, strconv.Itoa(def), func(value string) (any, error) {
+		numb, err := strconv.Atoi(value)
+		if err != nil || numb < mn {
+			return nil, NewErrInvalidInt(value, mn)
+		}
+
+		return numb, nil
+	})
+}
+
+// FloatParam returns a parameter holding a number in [mn, mx].
+func FloatParam(name, description string, def, mn, mx float64) Param {
+	return NewParam(name, description, strconv.FormatFloat(def, 'g', -1, 64), func(value string) (any, error) {
+		numb, err := strconv.ParseFloat(value, 64)
+		if err != nil || numb < mn || numb > mx {
+			return nil, NewErrInvalidFloat(value, mn, mx)
+		}
+
+		return numb, nil
+	})
+}
+
+// Entry is an algorithm registered under its name.
+type Entry[T any] struct {
+	Name        string
+	Description string
+	Params      []Param
+	// Supports reports whether the algorithm can handle the maze. A nil
+	// function means it handles any maze.
+	Supports func(data domain.MazeData) bool
+	// New creates the algorithm from parsed parameters.
+	New func(values Values) T
+}
+
+func (e Entry[T]) supports(data domain.MazeData) bool {
+	return e.Supports == nil || e.Supports(data)
+}
+
+// Registry holds algorithms in the order they were registered, which is
+// the order they are listed in.
+type Registry[T any] struct {
+	entries []Entry[T]
+}
+
+func New[T any]() *Registry[T] {
+	return &Registry[T]{
+		entries: make([]Entry[T], 0),
+	}
+}
+
+// Register adds the algorithm. Names are unique.
+func (r *Registry[T]) Register(entry Entry[T]) error {
+	if _, err := r.Lookup(entry.Name); err == nil {
+		return NewErrDuplicateAlgorithm(entry.Name)
+	}
+
+	r.entries = append(r.entries, entry)
+
+	return nil
+}
+
+// Entries returns all registered algorithms.
+func (r *Registry[T]) Entries() []Entry[T] {
+	return slices.Clone(r.entries)
+}
+
+// Supported returns the algorithms which can handle the maze.
+func (r *Registry[T]) Supported(data domain.MazeData) []Entry[T] {
+	res := make([]Entry[T], 0, len(r.entries))
+
+	for _, entry := range r.entries {
+		if entry.supports(data) {
+			res = append(res, entry)
+		}
+	}
+
+	return res
+}
+
+func (r *Registry[T]) Lookup(name string) (Entry[T], error) {
+	for _, entry := range r.entries {
+		if entry.Name == name {
+			return entry, nil
+		}
+	}
+
+	return Entry[T]{}, NewErrUnknownAlgorithm(name)
+}
+
+// Create parses the parameters and creates the algorithm. Parameters which
+// are missing or empty take their defaults.
+func (r *Registry[T]) Create(name string, params map[string]string) (T, error) {
+	var zero T
+
+	entry, err := r.Lookup(name)
+	if err != nil {
+		return zero, err
+	}
+
+	for param := range params {
+		if !slices.ContainsFunc(entry.Params, func(p Param) bool { return p.Name == param }) {
+			return zero, NewErrUnknownParam(name, param)
+		}
+	}
+
+	values := make(Values, len(entry.Params))
+
+	for _, param := range entry.Params {
+		value := params[param.Name]
+		if value == "" {
+			value = param.Default
+		}
+
+		values[param.Name], err = param.Parse(value)
+		if err != nil {
+			return zero, fmt.Errorf("parsing %s of %s: %w", param.Name, name, err)
+		}
+	}
+
+	return entry.New(values), nil
+}
@@ -0,0 +1,170 @@
+package registry_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/LLIEPJIOK/mazegenerator/internal/domain"
+	"github.com/LLIEPJIOK/mazegenerator/internal/registry"
+	"github.com/stretchr/testify/require"
+)
+
+type algorithm struct {
+	name  string
+	size  int
+	ratio float64
+}
+
+func newRegistry(t *testing.T) *registry.Registry[algorithm] {
+	t.Helper()
+
+	reg := registry.New[algorithm]()
+
+	require.NoError(t, reg.Register(registry.Entry[algorithm]{
+		Name:        "plain",
+		Description: "algorithm without parameters",
+		New:         func(registry.Values) algorithm { return algorithm{name: "plain"} },
+	}), "algorithm should be registered without error")
+
+	require.NoError(t, reg.Register(registry.Entry[algorithm]{
+		Name:        "tuned",
+		Description: "algorithm with parameters",
+		Params: []registry.Param{
+			registry.IntParam("size", "size", 3, 1),
+			registry.FloatParam("ratio", "ratio", 0.5, 0, 1),
+		},
+		Supports: func(data domain.MazeData) bool { return data.Topology == domain.Square },
+		New: func(values registry.Values) algorithm {
+			return algorithm{name: "tuned", size: values.Int("size"), ratio: values.Float("ratio")}
+		},
+	}), "algorithm should be registered without error")
+
+	return reg
+}
+
+func TestCreate(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		params   map[string]string
+		expected algorithm
+	}{
+		{
+			name:     "plain",
+			params:   nil,
+			expected: algorithm{name: "plain"},
+		},
+		{
+			name:     "tuned",
+			params:   map[string]string{},
+			expected: algorithm{name: "tuned", size: 3, ratio: 0.5},
+		},
+		{
+			name:     "tuned",
+			params:   map[string]string{"size": "10", "ratio": ""},
+			expected: algorithm{name: "tuned", size: 10, ratio: 0.5},
+		},
+		{
+			name:     "tuned",
+			params:   map[string]string{"size": "1", "ratio": "1"},
+			expected: algorithm{name: "tuned", size: 1, ratio: 1},
+		},
+	}
+
+	for i, testCase := range testCases {
+		t.Run(fmt.Sprintf("#%d", i+1), func(t *testing.T) {
+			t.Parallel()
+
+			got, err := newRegistry(t).Create(testCase.name, testCase.params)
+			require.NoError(t, err, "algorithm should be created without error")
+			require.Equal(t, testCase.expected, got, "algorithms should be equal")
+		})
+	}
+}
+
+func TestCreateWithError(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		params   map[string]string
+		expected error
+	}{
+		{
+			name:     "unknown",
+			params:   nil,
+			expected: registry.NewErrUnknownAlgorithm("unknown"),
+		},
+		{
+			name:     "plain",
+			params:   map[string]string{"size": "3"},
+			expected: registry.NewErrUnknownParam("plain", "size"),
+		},
+		{
+			name:     "tuned",
+			params:   map[string]string{"size": "0"},
+			expected: registry.NewErrInvalidInt("0", 1),
+		},
+		{
+			name:     "tuned",
+			params:   map[string]string{"size": "big"},
+			expected: registry.NewErrInvalidInt("big", 1),
+		},
+		{
+			name:     "tuned",
+			params:   map[string]string{"ratio": "1.5"},
+			expected: registry.NewErrInvalidFloat("1.5", 0, 1),
+		},
+	}
+
+	for i, testCase := range testCases {
+		t.Run(fmt.Sprintf("#%d", i+1), func(t *testing.T) {
+			t.Parallel()
+
+			_, err := newRegistry(t).Create(testCase.name, testCase.params)
+			require.ErrorIs(t, err, testCase.expected, "errors should be equal")
+		})
+	}
+}
+
+func TestRegisterDuplicate(t *testing.T) {
+	t.Parallel()
+
+	err := newRegistry(t).Register(registry.Entry[algorithm]{Name: "plain"})
+	require.ErrorIs(t, err, registry.NewErrDuplicateAlgorithm("plain"), "errors should be equal")
+}
+
+func TestSupported(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		topology domain.Topology
+		expected []string
+	}{
+		{
+			topology: domain.Square,
+			expected: []string{"plain", "tuned"},
+		},
+		{
+			topology: domain.Hex,
+			expected: []string{"plain"},
+		},
+	}
+
+	for i, testCase := range testCases {
+		t.Run(fmt.Sprintf("#%d", i+1), func(t *testing.T) {
+			t.Parallel()
+
+			data := domain.NewMazeData(5, 5, domain.NewCoord(0, 0), domain.NewCoord(4, 4))
+			data.Topology = testCase.topology
+
+			names := make([]string, 0)
+			for _, entry := range newRegistry(t).Supported(data) {
+				names = append(names, entry.Name)
+			}
+
+			require.Equal(t, testCase.expected, names, "algorithms should be equal")
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package renderer
+
+import (
+	"fmt"
+
+	"github.com/LLIEPJIOK/mazegenerator/internal/domain"
+)
+
+type ErrUnsupportedTopology struct {
+	topology domain.Topology
+}
+
+func NewErrUnsupportedTopology(topology domain.Topology) ErrUnsupportedTopology {
+	return ErrUnsupportedTopology{
+		topology: topology,
+	}
+}
+
+func (e ErrUnsupportedTopology) Error() string {
+	return fmt.Sprintf("svg renderer doesn't support %s grids", e.topology)
+}
@@ -0,0 +1,126 @@
+package renderer
+
+import (
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/LLIEPJIOK/mazegenerator/internal/domain"
+)
+
+// ringWidth is the width of a ring of a polar maze in pixels.
+const ringWidth = 20.0
+
+func cellColour(tpe domain.CellType) string {
+	switch tpe {
+	case domain.Wall:
+		return "#000000"
+	case domain.Passage:
+		return "#ffffff"
+	case domain.Money:
+		return "#ffff55"
+	case domain.Sand:
+		return "#c8a050"
+	case domain.River:
+		return "#3070e0"
+	case domain.Ambiguous:
+		return "#808080"
+	case domain.Path:
+		return "#e03030"
+	}
+
+	return "#ffffff"
+}
+
+// SVG draws polar mazes as concentric rings of cells.
+type SVG struct {
+	out io.Writer
+}
+
+func NewSVG(out io.Writer) *SVG {
+	return &SVG{
+		out: out,
+	}
+}
+
+func point(centre, radius, angle float64) (float64, float64) {
+	return centre + radius*math.Cos(angle), centre + radius*math.Sin(angle)
+}
+
+// sector returns the SVG path of the cell which lies between inner and outer
+// radiuses and between angles from and to, measured clockwise from the east.
+func sector(centre, inner, outer, from, to float64) string {
+	x1, y1 := point(centre, inner, from)
+	x2, y2 := point(centre, outer, from)
+	x3, y3 := point(centre, outer, to)
+	x4, y4 := point(centre, inner, to)
+
+	return fmt.Sprintf(
+		"M%.2f %.2f L%.2f %.2f A%.2f %.2f 0 0 1 %.2f %.2f L%.2f %.2f A%.2f %.2f 0 0 0 %.2f %.2f Z",
+		x1, y1, x2, y2, outer, outer, x3, y3, x4, y4, inner, inner, x1, y1,
+	)
+}
+
+// Render writes the maze with the path drawn over it as an SVG image.
+func (s *SVG) Render(maze domain.Maze, path []domain.Coord) error {
+	if maze.Data.Topology != domain.Polar {
+		return NewErrUnsupportedTopology(maze.Data.Topology)
+	}
+
+	onPath := make(map[domain.Coord]struct{}, len(path))
+	for _, coord := range path {
+		onPath[coord] = struct{}{}
+	}
+
+	colour := func(coord domain.Coord) string {
+		if _, ok := onPath[coord]; ok {
+			return cellColour(domain.Path)
+		}
+
+		return cellColour(maze.Cells[coord.Row][coord.Col])
+	}
+
+	size := 2 * ringWidth * float64(maze.Data.Height)
+	centre := size / 2
+
+	if _, err := fmt.Fprintf(
+		s.out,
+		"<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%.0f\" height=\"%.0f\" viewBox=\"0 0 %.0f %.0f\">\n",
+		size, size, size, size,
+	); err != nil {
+		return fmt.Errorf("write svg header: %w", err)
+	}
+
+	if _, err := fmt.Fprintf(
+		s.out,
+		"<circle cx=\"%.2f\" cy=\"%.2f\" r=\"%.2f\" fill=\"%s\"/>\n",
+		centre, centre, ringWidth, colour(domain.NewCoord(0, 0)),
+	); err != nil {
+		return fmt.Errorf("write centre cell: %w", err)
+	}
+
+	for ring := 1; ring < maze.Data.Height; ring++ {
+		cells := maze.Data.Topology.RowLength(ring, maze.Data.Width)
+		inner, outer := ringWidth*float64(ring), ringWidth*float64(ring+1)
+
+		for col := range cells {
+			from := 2 * math.Pi * float64(col) / float64(cells)
+			to := 2 * math.Pi * float64(col+1) / float64(cells)
+
+			if _, err := fmt.Fprintf(
+				s.out,
+				"<path d=\"%s\" fill=\"%s\"/>\n",
+				sector(centre, inner, outer, from, to),
+				colour(domain.NewCoord(ring, col)),
+			); err != nil {
+				return fmt.Errorf("write cell (%d, %d): %w", ring, col, err)
+			}
+		}
+	}
+
+	if _, err := fmt.Fprintln(s.out, "</svg>"); err != nil {
+		return fmt.Errorf("write svg footer: %w", err)
+	}
+
+	return nil
+}
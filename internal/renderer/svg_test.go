@@ -0,0 +1,112 @@
+package renderer_test
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/LLIEPJIOK/mazegenerator/internal/domain"
+	"github.com/LLIEPJIOK/mazegenerator/internal/renderer"
+	"github.com/stretchr/testify/require"
+)
+
+func wallCells(data domain.MazeData) [][]domain.CellType {
+	cells := make([][]domain.CellType, data.Height)
+
+	for row := range cells {
+		cells[row] = make([]domain.CellType, data.Width)
+	}
+
+	return cells
+}
+
+func TestSVGRender(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		rings    int
+		path     []domain.Coord
+		passages []domain.Coord
+	}{
+		{
+			rings: 1,
+			path:  []domain.Coord{domain.NewCoord(0, 0)},
+		},
+		{
+			rings: 3,
+			path:  []domain.Coord{domain.NewCoord(0, 0), domain.NewCoord(1, 0), domain.NewCoord(2, 0)},
+		},
+		{
+			rings:    5,
+			path:     []domain.Coord{domain.NewCoord(0, 0), domain.NewCoord(1, 0)},
+			passages: []domain.Coord{domain.NewCoord(2, 1), domain.NewCoord(3, 2), domain.NewCoord(4, 3)},
+		},
+	}
+
+	for i, testCase := range testCases {
+		t.Run(fmt.Sprintf("#%d", i+1), func(t *testing.T) {
+			t.Parallel()
+
+			data := domain.NewPolarMazeData(testCase.rings)
+			cells := wallCells(data)
+
+			for _, coord := range testCase.passages {
+				cells[coord.Row][coord.Col] = domain.Passage
+			}
+
+			var buf bytes.Buffer
+
+			err := renderer.NewSVG(&buf).Render(domain.NewMaze(data, cells), testCase.path)
+			require.NoError(t, err, "maze should be rendered without error")
+
+			cnt := 0
+			for ring := 1; ring < testCase.rings; ring++ {
+				cnt += domain.Polar.RowLength(ring, data.Width)
+			}
+
+			svg := buf.String()
+
+			require.True(t, strings.HasPrefix(svg, "<svg "), "image should start with the svg tag")
+			require.True(t, strings.HasSuffix(svg, "</svg>\n"), "image should end with the svg tag")
+			require.Equal(t, 1, strings.Count(svg, "<circle "), "centre should be drawn as a circle")
+			require.Equal(t, cnt, strings.Count(svg, "<path "), "every cell out of the centre should be a path")
+			require.Equal(t, len(testCase.path), strings.Count(svg, "fill=\"#e03030\""), "path cells should be red")
+			require.Equal(t, len(testCase.passages), strings.Count(svg, "fill=\"#ffffff\""), "passages should be white")
+			require.Equal(
+				t,
+				cnt+1-len(testCase.path)-len(testCase.passages),
+				strings.Count(svg, "fill=\"#000000\""),
+				"other cells should be black walls",
+			)
+		})
+	}
+}
+
+func TestSVGRenderWithError(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		topology domain.Topology
+	}{
+		{topology: domain.Square},
+		{topology: domain.Hex},
+		{topology: domain.Torus},
+	}
+
+	for i, testCase := range testCases {
+		t.Run(fmt.Sprintf("#%d", i+1), func(t *testing.T) {
+			t.Parallel()
+
+			data := domain.NewMazeData(3, 3, domain.NewCoord(0, 0), domain.NewCoord(2, 2))
+			data.Topology = testCase.topology
+
+			var buf bytes.Buffer
+
+			err := renderer.NewSVG(&buf).Render(domain.NewMaze(data, wallCells(data)), nil)
+
+			require.ErrorAs(t, err, &renderer.ErrUnsupportedTopology{}, "topology should be unsupported")
+			require.Empty(t, buf.String(), "nothing should be written")
+		})
+	}
+}